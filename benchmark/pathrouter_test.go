@@ -4,6 +4,7 @@
 package benchmark_test
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -115,3 +116,54 @@ func buildPathrouterForDistributionAPI() http.Handler {
 		)),
 	)
 }
+
+func BenchmarkRouterWithLargeFanOut(b *testing.B) {
+	// This benchmark uses a synthetic API with a large number of toplevel resource types,
+	// where a Choice() has many Element() children that need to be checked.
+	// The request targets the last resource type, which is the worst case for first-match-wins routing.
+
+	const resourceCount = 100
+	resourceName := func(idx int) string {
+		return fmt.Sprintf("resource%03d", idx)
+	}
+
+	testWith := func(h http.Handler) func(b *testing.B) {
+		return func(b *testing.B) {
+			path := fmt.Sprintf("/v1/%s/42", resourceName(resourceCount-1))
+			for b.Loop() {
+				req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+				assert.Equal(b, rec.Code, http.StatusForbidden)
+				if b.Failed() {
+					b.FailNow()
+				}
+			}
+		}
+	}
+
+	forbidden := func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}
+	muxRouter := mux.NewRouter()
+	for idx := range resourceCount {
+		muxRouter.Methods("GET").Path(fmt.Sprintf("/v1/%s/", resourceName(idx))).HandlerFunc(forbidden)
+		muxRouter.Methods("GET").Path(fmt.Sprintf("/v1/%s/{id}", resourceName(idx))).HandlerFunc(forbidden)
+	}
+
+	branches := make([]pr.Matcher, resourceCount)
+	for idx := range resourceCount {
+		branches[idx] = pr.Element(resourceName(idx), pr.Choice(
+			pr.Element("/", pr.Handlers(pr.ByMethod{
+				http.MethodGet: func(w http.ResponseWriter, r *http.Request, vars map[string]string) { forbidden(w, r) },
+			})),
+			pr.Variable("id", pr.Handlers(pr.ByMethod{
+				http.MethodGet: func(w http.ResponseWriter, r *http.Request, vars map[string]string) { forbidden(w, r) },
+			})),
+		))
+	}
+	prRouter := pr.Element("v1", pr.Choice(branches...))
+
+	b.Run("platform=gorilla-mux", testWith(muxRouter))
+	b.Run("platform=gg-pathrouter", testWith(prRouter))
+}
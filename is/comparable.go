@@ -3,6 +3,8 @@
 
 package is
 
+import "slices"
+
 // EqualTo(b)(a) is the same as a == b.
 func EqualTo[T comparable](rhs T) func(T) bool {
 	return func(lhs T) bool {
@@ -16,3 +18,25 @@ func DifferentFrom[T comparable](rhs T) func(T) bool {
 		return lhs != rhs
 	}
 }
+
+// OneOf(b, c)(a) is the same as a == b || a == c.
+// If no values are given, the result is always false.
+func OneOf[T comparable](values ...T) func(T) bool {
+	return func(lhs T) bool {
+		return slices.Contains(values, lhs)
+	}
+}
+
+// Zero(a) is the same as a == T{}, where T{} is the zero value of T.
+// Unlike the other functions in this package, Zero is a predicate by itself, e.g. Some(0).IsSomeAnd(is.Zero).
+func Zero[T comparable](value T) bool {
+	var zero T
+	return value == zero
+}
+
+// NonZero(a) is the same as a != T{}, where T{} is the zero value of T.
+// Unlike the other functions in this package, NonZero is a predicate by itself, e.g. Some(1).IsSomeAnd(is.NonZero).
+func NonZero[T comparable](value T) bool {
+	var zero T
+	return value != zero
+}
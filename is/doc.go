@@ -25,4 +25,9 @@
 //	case maxSize.IsSomeAnd(func(value uint64) bool { return maxSize < size }):
 //	// rewritten
 //	case maxSize.IsSomeAnd(is.LessThan(size)):
+//
+// Predicates can be combined with [All], [Any] and [Not]:
+//
+//	isValidPort := is.All(is.Between(1, 65535), is.Not(is.OneOf(blockedPorts...)))
+//	case port.IsSomeAnd(isValidPort):
 package is // import "go.xyrillian.de/gg/is"
@@ -4,6 +4,7 @@
 package is_test
 
 import (
+	"regexp"
 	"testing"
 	"time"
 
@@ -18,6 +19,40 @@ func TestComparable(t *testing.T) {
 
 	assert.Equal(t, Some("foo").IsSomeAnd(is.DifferentFrom("foo")), false)
 	assert.Equal(t, Some("bar").IsSomeAnd(is.DifferentFrom("foo")), true)
+
+	assert.Equal(t, Some("foo").IsSomeAnd(is.OneOf("foo", "bar")), true)
+	assert.Equal(t, Some("bar").IsSomeAnd(is.OneOf("foo", "bar")), true)
+	assert.Equal(t, Some("qux").IsSomeAnd(is.OneOf("foo", "bar")), false)
+	assert.Equal(t, Some("foo").IsSomeAnd(is.OneOf[string]()), false)
+
+	assert.Equal(t, Some(0).IsSomeAnd(is.Zero), true)
+	assert.Equal(t, Some(1).IsSomeAnd(is.Zero), false)
+	assert.Equal(t, Some("").IsSomeAnd(is.NonZero), false)
+	assert.Equal(t, Some("foo").IsSomeAnd(is.NonZero), true)
+}
+
+func TestLogic(t *testing.T) {
+	isSmallEven := is.All(is.Below(10), func(x int) bool { return x%2 == 0 })
+	assert.Equal(t, Some(4).IsSomeAnd(isSmallEven), true)
+	assert.Equal(t, Some(5).IsSomeAnd(isSmallEven), false)
+	assert.Equal(t, Some(12).IsSomeAnd(isSmallEven), false)
+	assert.Equal(t, Some(12).IsSomeAnd(is.All[int]()), true)
+
+	isOutlier := is.Any(is.Below(0), is.Above(100))
+	assert.Equal(t, Some(-1).IsSomeAnd(isOutlier), true)
+	assert.Equal(t, Some(50).IsSomeAnd(isOutlier), false)
+	assert.Equal(t, Some(101).IsSomeAnd(isOutlier), true)
+	assert.Equal(t, Some(101).IsSomeAnd(is.Any[int]()), false)
+
+	assert.Equal(t, Some(50).IsSomeAnd(is.Not(isOutlier)), true)
+	assert.Equal(t, Some(101).IsSomeAnd(is.Not(isOutlier)), false)
+
+	// evaluation stops early
+	calls := 0
+	count := func(int) bool { calls++; return true }
+	is.All(is.Below(0), count)(5)
+	is.Any(is.Above(0), count)(5)
+	assert.Equal(t, calls, 0)
 }
 
 func TestOrdered(t *testing.T) {
@@ -36,6 +71,38 @@ func TestOrdered(t *testing.T) {
 	assert.Equal(t, Some(3).IsSomeAnd(is.NotBelow(4)), false)
 	assert.Equal(t, Some(4).IsSomeAnd(is.NotBelow(4)), true)
 	assert.Equal(t, Some(5).IsSomeAnd(is.NotBelow(4)), true)
+
+	for value, expected := range map[int][3]bool{
+		// values are the results for Between(), StrictlyBetween() and InRange()
+		2: {false, false, false},
+		3: {true, false, true},
+		4: {true, true, true},
+		5: {true, false, false},
+		6: {false, false, false},
+	} {
+		assert.Equal(t, Some(value).IsSomeAnd(is.Between(3, 5)), expected[0])
+		assert.Equal(t, Some(value).IsSomeAnd(is.StrictlyBetween(3, 5)), expected[1])
+		assert.Equal(t, Some(value).IsSomeAnd(is.InRange(3, 5)), expected[2])
+	}
+
+	// durations are ordered as well
+	assert.Equal(t, Some(90*time.Second).IsSomeAnd(is.Between(time.Minute, time.Hour)), true)
+	assert.Equal(t, Some(90*time.Minute).IsSomeAnd(is.Between(time.Minute, time.Hour)), false)
+}
+
+func TestString(t *testing.T) {
+	assert.Equal(t, Some("foobar").IsSomeAnd(is.Prefix("foo")), true)
+	assert.Equal(t, Some("foobar").IsSomeAnd(is.Prefix("bar")), false)
+
+	assert.Equal(t, Some("foobar").IsSomeAnd(is.Suffix("foo")), false)
+	assert.Equal(t, Some("foobar").IsSomeAnd(is.Suffix("bar")), true)
+
+	assert.Equal(t, Some("foobar").IsSomeAnd(is.Substring("oba")), true)
+	assert.Equal(t, Some("foobar").IsSomeAnd(is.Substring("qux")), false)
+
+	rx := regexp.MustCompile(`^[a-z]+-[0-9]+$`)
+	assert.Equal(t, Some("foo-42").IsSomeAnd(is.Matching(rx)), true)
+	assert.Equal(t, Some("foo-bar").IsSomeAnd(is.Matching(rx)), false)
 }
 
 func TestTime(t *testing.T) {
@@ -58,4 +125,14 @@ func TestTime(t *testing.T) {
 	assert.Equal(t, Some(t1).IsSomeAnd(is.NotBefore(t2)), false)
 	assert.Equal(t, Some(t2).IsSomeAnd(is.NotBefore(t2)), true)
 	assert.Equal(t, Some(t3).IsSomeAnd(is.NotBefore(t2)), true)
+
+	assert.Equal(t, Some(t1).IsSomeAnd(is.During(t1, t2)), true)
+	assert.Equal(t, Some(t2).IsSomeAnd(is.During(t1, t2)), false)
+	assert.Equal(t, Some(t2).IsSomeAnd(is.During(t2, t3)), true)
+	assert.Equal(t, Some(t3).IsSomeAnd(is.During(t1, t2)), false)
+
+	assert.Equal(t, Some(t1).IsSomeAnd(is.Near(t2, time.Second)), true)
+	assert.Equal(t, Some(t3).IsSomeAnd(is.Near(t2, time.Second)), true)
+	assert.Equal(t, Some(t3).IsSomeAnd(is.Near(t1, time.Second)), false)
+	assert.Equal(t, Some(t1).IsSomeAnd(is.Near(t3, time.Second)), false)
 }
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package is
+
+// All(p, q)(a) is the same as p(a) && q(a).
+// Predicates are evaluated in order, and evaluation stops at the first predicate that returns false.
+// If no predicates are given, the result is always true.
+func All[T any](predicates ...func(T) bool) func(T) bool {
+	return func(value T) bool {
+		for _, predicate := range predicates {
+			if !predicate(value) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Any(p, q)(a) is the same as p(a) || q(a).
+// Predicates are evaluated in order, and evaluation stops at the first predicate that returns true.
+// If no predicates are given, the result is always false.
+func Any[T any](predicates ...func(T) bool) func(T) bool {
+	return func(value T) bool {
+		for _, predicate := range predicates {
+			if predicate(value) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not(p)(a) is the same as !p(a).
+func Not[T any](predicate func(T) bool) func(T) bool {
+	return func(value T) bool {
+		return !predicate(value)
+	}
+}
@@ -32,3 +32,25 @@ func NotBelow[T cmp.Ordered](rhs T) func(T) bool {
 		return lhs >= rhs
 	}
 }
+
+// Between(b, c)(a) is the same as b <= a && a <= c.
+func Between[T cmp.Ordered](lower, upper T) func(T) bool {
+	return func(value T) bool {
+		return lower <= value && value <= upper
+	}
+}
+
+// StrictlyBetween(b, c)(a) is the same as b < a && a < c.
+func StrictlyBetween[T cmp.Ordered](lower, upper T) func(T) bool {
+	return func(value T) bool {
+		return lower < value && value < upper
+	}
+}
+
+// InRange(b, c)(a) is the same as b <= a && a < c.
+// This matches the usual half-open interval semantics of Go, as in slice expressions like s[b:c].
+func InRange[T cmp.Ordered](lower, upper T) func(T) bool {
+	return func(value T) bool {
+		return lower <= value && value < upper
+	}
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package is
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Prefix(b)(a) is the same as strings.HasPrefix(a, b).
+func Prefix(prefix string) func(string) bool {
+	return func(value string) bool {
+		return strings.HasPrefix(value, prefix)
+	}
+}
+
+// Suffix(b)(a) is the same as strings.HasSuffix(a, b).
+func Suffix(suffix string) func(string) bool {
+	return func(value string) bool {
+		return strings.HasSuffix(value, suffix)
+	}
+}
+
+// Substring(b)(a) is the same as strings.Contains(a, b).
+func Substring(substr string) func(string) bool {
+	return func(value string) bool {
+		return strings.Contains(value, substr)
+	}
+}
+
+// Matching(rx)(a) is the same as rx.MatchString(a).
+//
+// Note that the regex is not anchored automatically.
+// To match the entire string, the regex needs to start with ^ and end with $.
+func Matching(rx *regexp.Regexp) func(string) bool {
+	return rx.MatchString
+}
@@ -32,3 +32,15 @@ func NotBefore(rhs time.Time) func(time.Time) bool {
 		return !lhs.Before(rhs)
 	}
 }
+
+// During(b, c)(a) is the same as !a.Before(b) && a.Before(c).
+// Like [InRange], this uses half-open interval semantics, so that adjacent time windows do not overlap.
+func During(start, end time.Time) func(time.Time) bool {
+	return All(NotBefore(start), Before(end))
+}
+
+// Near(b, d)(a) is true if and only if a is at most the duration d away from b (in either direction).
+// This is useful for comparing timestamps that have gone through a lossy serialization, or have been generated by a clock with limited precision.
+func Near(reference time.Time, tolerance time.Duration) func(time.Time) bool {
+	return All(NotBefore(reference.Add(-tolerance)), NotAfter(reference.Add(tolerance)))
+}
@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package microprom
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// EscapingScheme is an enum, defining how [MetricSet] renders metric names and label names
+// that are not valid according to the legacy rules of the Prometheus exposition formats.
+// In the [Handler], the escaping scheme is negotiated through the "escaping" parameter of the Accept header.
+//
+// Names that are valid according to the legacy rules are never changed, regardless of the escaping scheme.
+// For metric names, the legacy rules are described on type [MetricFamilyName]. For label names, they are described on func [NewLabelNames].
+//
+// For example, the metric name "http.server.duration" will be rendered as follows:
+//
+//	EscapingUnderscores: http_server_duration
+//	EscapingAllowUTF8:   "http.server.duration" (quoted in text formats)
+//	EscapingDots:        http_dot_server_dot_duration
+//	EscapingValues:      U__http_2e_server_2e_duration
+type EscapingScheme uint
+
+const (
+	// EscapingUnderscores replaces each invalid character with an underscore.
+	// This is the default escaping scheme of promhttp, and the one used by [NewMetricSet].
+	EscapingUnderscores EscapingScheme = iota
+	// EscapingAllowUTF8 does not escape names. In text formats, names that are not valid according to the legacy rules are quoted instead.
+	// Metric names are quoted by moving them into the label set, e.g. {"http.server.duration",method="GET"}.
+	EscapingAllowUTF8
+	// EscapingDots replaces "." with "_dot_", "_" with "__", and each other invalid character with "__".
+	EscapingDots
+	// EscapingValues prepends "U__", replaces "_" with "__", and replaces each other invalid character with its Unicode codepoint in hexadecimal, surrounded by underscores.
+	EscapingValues
+)
+
+var escapingSchemeNames = [...]string{"underscores", "allow-utf-8", "dots", "values"}
+
+// parseEscapingScheme parses the value of the "escaping" parameter of a media type.
+func parseEscapingScheme(value string) (EscapingScheme, bool) {
+	for idx, name := range escapingSchemeNames {
+		if name == value {
+			return EscapingScheme(idx), true
+		}
+	}
+	return 0, false
+}
+
+// isLegacyName returns whether the name is valid according to the legacy rules.
+// For metric names, colons are allowed. For label names, they are not.
+func isLegacyName(name string, allowColon bool) bool {
+	if name == "" {
+		return false
+	}
+	for idx, r := range name {
+		if !isLegacyRune(r, idx, allowColon) {
+			return false
+		}
+	}
+	return true
+}
+
+func isLegacyRune(r rune, idx int, allowColon bool) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_' || (r == ':' && allowColon) || (r >= '0' && r <= '9' && idx > 0)
+}
+
+// validateName checks the basic requirements for all metric names and label names.
+func validateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%q is empty", name)
+	}
+	if !utf8.ValidString(name) {
+		return fmt.Errorf("%q is not valid UTF-8", name)
+	}
+	return nil
+}
+
+// escapeName applies the given escaping scheme to a name that is not valid according to the legacy rules.
+// For EscapingAllowUTF8, the name is returned unchanged.
+func escapeName(name string, scheme EscapingScheme, allowColon bool) string {
+	var b strings.Builder
+	switch scheme {
+	case EscapingUnderscores:
+		for idx, r := range name {
+			if isLegacyRune(r, idx, allowColon) {
+				b.WriteRune(r)
+			} else {
+				b.WriteByte('_')
+			}
+		}
+	case EscapingDots:
+		for idx, r := range name {
+			switch {
+			case r == '_':
+				b.WriteString("__")
+			case r == '.':
+				b.WriteString("_dot_")
+			case isLegacyRune(r, idx, allowColon):
+				b.WriteRune(r)
+			default:
+				b.WriteString("__")
+			}
+		}
+	case EscapingValues:
+		b.WriteString("U__")
+		for idx, r := range name {
+			switch {
+			case r == '_':
+				b.WriteString("__")
+			case isLegacyRune(r, idx, allowColon):
+				b.WriteRune(r)
+			default:
+				b.WriteByte('_')
+				b.WriteString(strconv.FormatInt(int64(r), 16))
+				b.WriteByte('_')
+			}
+		}
+	default:
+		return name
+	}
+	return b.String()
+}
+
+// quoteName renders a name in the quoted form used by the text formats, using the same escape sequences as for label values.
+func quoteName(name string) string {
+	return `"` + escapeLabelValue(name) + `"`
+}
+
+// escapeLabelValue applies the escape sequences required for label values in the text formats.
+func escapeLabelValue(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "\"", "\\\"")
+	value = strings.ReplaceAll(value, "\n", "\\n")
+	return value
+}
+
+// familyNames holds the rendered names for a metric family, according to the syntax and escaping scheme of a MetricSet.
+// Names that need quoting are rendered in their quoted form, which always starts with `"`.
+type familyNames struct {
+	header  string // for HELP and TYPE lines
+	metric  string // for samples (for summaries: the quantile samples)
+	bucket  string // only for histograms
+	sum     string // only for histograms and summaries
+	count   string // only for histograms and summaries
+	created string // only for counters
+}
+
+// familyNames computes the rendered names for the given metric family.
+func (ms *MetricSet) familyNames(name MetricFamilyName, info MetricFamilyInfo) familyNames {
+	// NOTE: Only the family name is escaped. The suffixes are valid legacy names anyway,
+	//       and escaping them would lead to results like "foo_dot_bar__total" for EscapingDots.
+	familyName := string(name)
+	typeSuffix := metricTypeSuffixes[info.Type]
+	render := func(suffix string) string {
+		switch {
+		case isLegacyName(familyName, true):
+			return familyName + suffix
+		case ms.escaping != EscapingAllowUTF8:
+			return escapeName(familyName, ms.escaping, true) + suffix
+		case ms.syntax == SyntaxProtobuf:
+			return familyName + suffix
+		default:
+			return quoteName(familyName + suffix)
+		}
+	}
+
+	result := familyNames{metric: render(typeSuffix)}
+	if info.Type == MetricTypeCounter {
+		result.created = render("_created")
+	}
+	if info.Type == MetricTypeHistogram {
+		result.bucket = render("_bucket")
+	}
+	if info.Type == MetricTypeHistogram || info.Type == MetricTypeSummary {
+		result.sum = render("_sum")
+		result.count = render("_count")
+	}
+
+	switch {
+	case ms.syntax != SyntaxOpenMetricsV1:
+		// Prometheus Text Format and protobuf format do not distinguish between metric names and metric family names
+		result.header = result.metric
+	case ms.escaping == EscapingAllowUTF8 && !isLegacyName(familyName, true):
+		result.header = quoteName(familyName)
+	default:
+		result.header = strings.TrimSuffix(result.metric, typeSuffix)
+	}
+	return result
+}
@@ -11,7 +11,9 @@ import (
 	"maps"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"go.xyrillian.de/gg/internal/accept"
 )
@@ -29,6 +31,15 @@ import (
 //   - This behavior may be useful in tests because it produces deterministic output.
 //
 // When asserting on metrics in tests, it may be useful to set SortOutput equal to testing.Testing().
+//
+// If Streaming is true:
+//   - Each call to [MetricSet.Add] (or its variants) writes the metric into the response immediately.
+//     Memory usage is therefore bounded regardless of how many metrics are reported.
+//   - All metrics of the same family must be added consecutively. Metric families will be printed in the order in which they were added.
+//   - SortOutput has no effect.
+//   - If Collect returns an error before any output has been sent, a "500 Internal Server Error" response is generated as usual.
+//     Otherwise, the response is aborted (by panicking with [http.ErrAbortHandler]) to ensure that the scraper detects the failed scrape.
+//   - In the protobuf format, metric families larger than 64 KiB will be split into multiple MetricFamily messages.
 type Handler struct {
 	// The set of metric families for which this handler can report metrics.
 	Families map[MetricFamilyName]MetricFamilyInfo
@@ -38,6 +49,8 @@ type Handler struct {
 
 	// See documentation on type for details.
 	SortOutput bool
+	// See documentation on type for details.
+	Streaming bool
 }
 
 var _ http.Handler = Handler{}
@@ -61,19 +74,36 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		"application/openmetrics-text; version=1.0.0; charset=utf-8; escaping=allow-utf-8",
 		"application/openmetrics-text; version=1.0.0; charset=utf-8; escaping=dots",
 		"application/openmetrics-text; version=1.0.0; charset=utf-8; escaping=values",
+		"application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited; escaping=underscores",
+		"application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited; escaping=allow-utf-8",
+		"application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited; escaping=dots",
+		"application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited; escaping=values",
 	).Unpack()
 	if !ok {
-		http.Error(w, "supported formats are text/plain and application/openmetrics-text", http.StatusNotAcceptable)
+		http.Error(w, "supported formats are text/plain, application/openmetrics-text and application/vnd.google.protobuf", http.StatusNotAcceptable)
 		return
 	}
 
 	w.Header().Set("Content-Type", acceptedFormat)
 	syntax := SyntaxPrometheusLegacy
-	if strings.HasPrefix(acceptedFormat, "application/openmetrics-text; version=1.0.0;") {
+	switch {
+	case strings.HasPrefix(acceptedFormat, "application/openmetrics-text; version=1.0.0;"):
 		syntax = SyntaxOpenMetricsV1
+	case strings.HasPrefix(acceptedFormat, "application/vnd.google.protobuf;"):
+		syntax = SyntaxProtobuf
+	}
+	_, escapingParam, _ := strings.Cut(acceptedFormat, "; escaping=")
+	escaping, ok := parseEscapingScheme(escapingParam)
+	if !ok {
+		panic("unreachable") // all offered formats have a known escaping parameter
 	}
 
-	ms := NewMetricSet(syntax, h.Families)
+	if h.Streaming {
+		h.serveStreaming(w, r, NewMetricSetWithEscaping(syntax, escaping, h.Families))
+		return
+	}
+
+	ms := NewMetricSetWithEscaping(syntax, escaping, h.Families)
 	err := h.Collect(r.Context(), ms)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -84,18 +114,23 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	bw := bufio.NewWriter(w)
 	if h.SortOutput {
 		for _, familyName := range slices.Sorted(maps.Keys(h.Families)) {
-			h.printMetricFamily(bw, syntax, familyName, h.Families[familyName], ms.metrics[familyName])
+			h.printMetricFamily(bw, ms, familyName, h.Families[familyName], ms.metrics[familyName])
 		}
 	} else {
 		for familyName, familyInfo := range h.Families {
-			h.printMetricFamily(bw, syntax, familyName, familyInfo, ms.metrics[familyName])
+			h.printMetricFamily(bw, ms, familyName, familyInfo, ms.metrics[familyName])
 		}
 	}
 
-	if syntax != SyntaxPrometheusLegacy {
+	h.finishResponse(w, bw, syntax)
+}
+
+// finishResponse writes the end of the response body and flushes the buffered writer.
+func (h Handler) finishResponse(w http.ResponseWriter, bw *bufio.Writer, syntax Syntax) {
+	if syntax == SyntaxOpenMetricsV1 {
 		fmt.Fprint(bw, "# EOF\n")
 	}
-	err = bw.Flush()
+	err := bw.Flush()
 	if err != nil {
 		// We do not have a way to log this because we do not know what log library the application uses,
 		// and I also do not want to add a dependency injection slot to type Handler for this one extremely unlikely codepath.
@@ -105,67 +140,122 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (h Handler) printMetricFamily(w io.Writer, syntax Syntax, familyName MetricFamilyName, info MetricFamilyInfo, metrics []metric) {
+func (h Handler) printMetricFamily(w io.Writer, ms *MetricSet, familyName MetricFamilyName, info MetricFamilyInfo, metrics []metric) {
 	if len(metrics) == 0 {
 		return
 	}
 
-	var metricName string
-	switch info.Type {
-	case MetricTypeGauge:
-		metricName = string(familyName)
-	case MetricTypeCounter:
-		metricName = string(familyName) + "_total"
-	case MetricTypeInfo:
-		metricName = string(familyName) + "_info"
-	default:
-		panic("unreachable") // NewMetricSet() should have rejected unknown MetricType values
-	}
-
-	if syntax == SyntaxPrometheusLegacy {
-		// Prometheus Text Format does not distinguish between metric names and metric family names
-		familyName = MetricFamilyName(metricName)
-	}
-
-	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", familyName, info.Help, familyName, metricTypeNames[info.Type])
-
 	if h.SortOutput {
 		slices.SortFunc(metrics, func(lhs, rhs metric) int {
 			return strings.Compare(string(lhs.labels), string(rhs.labels))
 		})
 	}
+
+	names := ms.familyNames(familyName, info)
+	if ms.syntax == SyntaxProtobuf {
+		var pw protobufWriter
+		pw.StartFamily(names.metric, info)
+		for _, m := range metrics {
+			pw.AddMetric(info, m)
+		}
+		pw.FinishFamily(w)
+		return
+	}
+
+	printFamilyHeader(w, names, info)
 	for _, m := range metrics {
-		if m.labels == "" {
-			fmt.Fprintf(w, "%s ", metricName)
-		} else {
-			fmt.Fprintf(w, "%s{%s} ", metricName, m.labels)
+		printMetric(w, ms.syntax, names, info, m)
+	}
+}
+
+// printFamilyHeader prints the HELP and TYPE lines for a metric family in one of the text formats.
+func printFamilyHeader(w io.Writer, names familyNames, info MetricFamilyInfo) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", names.header, info.Help, names.header, metricTypeNames[info.Type])
+}
+
+// printMetric prints all lines for a single metric in one of the text formats.
+func printMetric(w io.Writer, syntax Syntax, names familyNames, info MetricFamilyInfo, m metric) {
+	switch info.Type {
+	case MetricTypeHistogram:
+		for _, b := range m.dist.buckets {
+			printSample(w, names.bucket, m.labels, "le", formatFloat(syntax, b.UpperBound), strconv.FormatUint(b.CumulativeCount, 10))
 		}
-		if syntax == SyntaxPrometheusLegacy {
-			fmt.Fprintf(w, "%g\n", m.value)
+		printSample(w, names.sum, m.labels, "", "", formatFloat(syntax, m.value))
+		printSample(w, names.count, m.labels, "", "", strconv.FormatUint(m.dist.count, 10))
+	case MetricTypeSummary:
+		for _, q := range m.dist.quantiles {
+			printSample(w, names.metric, m.labels, "quantile", formatFloat(syntax, q.Quantile), formatFloat(syntax, q.Value))
+		}
+		printSample(w, names.sum, m.labels, "", "", formatFloat(syntax, m.value))
+		printSample(w, names.count, m.labels, "", "", strconv.FormatUint(m.dist.count, 10))
+	default:
+		if m.opts == nil {
+			printSample(w, names.metric, m.labels, "", "", formatFloat(syntax, m.value))
 		} else {
-			// TODO: ugly
-			fi := floatInspector{inner: w}
-			fmt.Fprintf(&fi, "%g", m.value)
-			if fi.clearlyFloat {
-				fmt.Fprintf(w, "\n")
-			} else {
-				fmt.Fprintf(w, ".0\n")
-			}
+			printSampleWithOptions(w, syntax, names, m)
+		}
+	}
+}
+
+// printSampleWithOptions prints the lines for a metric that was added with AddWithOptions().
+// This only happens in SyntaxOpenMetricsV1, so the OpenMetrics syntax for timestamps and exemplars can be used unconditionally.
+func printSampleWithOptions(w io.Writer, syntax Syntax, names familyNames, m metric) {
+	var timestamp string
+	if t, ok := m.opts.Timestamp.Unpack(); ok {
+		timestamp = " " + formatTimestamp(t)
+	}
+	var exemplar string
+	if e, ok := m.opts.Exemplar.Unpack(); ok {
+		exemplar = fmt.Sprintf(" # {%s} %s", e.Labels, formatFloat(syntax, e.Value))
+		if t, ok := e.Timestamp.Unpack(); ok {
+			exemplar += " " + formatTimestamp(t)
 		}
 	}
+
+	printSample(w, names.metric, m.labels, "", "", formatFloat(syntax, m.value)+timestamp+exemplar)
+	if created, ok := m.opts.Created.Unpack(); ok {
+		printSample(w, names.created, m.labels, "", "", formatTimestamp(created)+timestamp)
+	}
 }
 
-type floatInspector struct {
-	inner        io.Writer
-	clearlyFloat bool
+// formatTimestamp formats a timestamp as seconds since the Unix epoch, with millisecond precision.
+func formatTimestamp(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', -1, 64)
 }
 
-func (fi *floatInspector) Write(buf []byte) (int, error) {
-	if slices.Contains(buf, '.') {
-		fi.clearlyFloat = true
+// printSample prints a single line of metric output.
+// If extraLabelName is not empty, the respective label will be appended to the label set (e.g. "le" for histogram buckets).
+func printSample(w io.Writer, metricName string, labels Labels, extraLabelName, extraLabelValue, value string) {
+	// quoted metric names are moved into the label set, e.g. {"http.server.duration",method="GET"}
+	isQuoted := strings.HasPrefix(metricName, `"`)
+	if !isQuoted {
+		fmt.Fprint(w, metricName)
 	}
-	if slices.Contains(buf, 'e') {
-		fi.clearlyFloat = true
+
+	if isQuoted || labels != "" || extraLabelName != "" {
+		separator := "{"
+		if isQuoted {
+			fmt.Fprint(w, separator, metricName)
+			separator = ","
+		}
+		if labels != "" {
+			fmt.Fprint(w, separator, string(labels))
+			separator = ","
+		}
+		if extraLabelName != "" {
+			fmt.Fprintf(w, "%s%s=%q", separator, extraLabelName, extraLabelValue)
+		}
+		fmt.Fprint(w, "}")
+	}
+	fmt.Fprintf(w, " %s\n", value)
+}
+
+// formatFloat formats a sample value or a numeric label value (like "le" or "quantile").
+func formatFloat(syntax Syntax, value float64) string {
+	result := strconv.FormatFloat(value, 'g', -1, 64)
+	// OpenMetrics requires floats to be clearly recognizable as floats, e.g. "1.0" instead of "1"
+	if syntax != SyntaxPrometheusLegacy && !strings.ContainsAny(result, ".eIN") {
+		result += ".0"
 	}
-	return fi.inner.Write(buf)
+	return result
 }
@@ -5,16 +5,21 @@ package microprom_test
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"io"
 	"maps"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"go.xyrillian.de/gg/assert"
 	"go.xyrillian.de/gg/microprom"
+	. "go.xyrillian.de/gg/option"
 )
 
 func TestHandlerBasic(t *testing.T) {
@@ -53,6 +58,398 @@ process_info{version="1.2.3"} 1
 	`)+"\n")
 }
 
+func TestHandlerHistogramAndSummary(t *testing.T) {
+	h := microprom.Handler{
+		Families: map[microprom.MetricFamilyName]microprom.MetricFamilyInfo{
+			"request_duration_seconds": {
+				Type: microprom.MetricTypeHistogram,
+				Help: "How long requests took.",
+			},
+			"response_size_bytes": {
+				Type: microprom.MetricTypeSummary,
+				Help: "How large responses were.",
+			},
+		},
+		SortOutput: true,
+		Collect: func(ctx context.Context, ms *microprom.MetricSet) error {
+			names := microprom.NewLabelNames("method")
+			ms.AddHistogram("request_duration_seconds", ms.FormatLabels(names, "GET"), []microprom.Bucket{
+				{UpperBound: 0.1, CumulativeCount: 2},
+				{UpperBound: 1, CumulativeCount: 5},
+				// +Inf bucket will be added automatically
+			}, 3.25, 6)
+			ms.AddHistogram("request_duration_seconds", ms.FormatLabels(names, "POST"), []microprom.Bucket{
+				{UpperBound: 0.1, CumulativeCount: 0},
+				{UpperBound: 1, CumulativeCount: 1},
+				{UpperBound: math.Inf(+1), CumulativeCount: 1},
+			}, 0.5, 1)
+			ms.AddSummary("response_size_bytes", "", []microprom.Quantile{
+				{Quantile: 0.5, Value: 1024},
+				{Quantile: 0.99, Value: 65536.5},
+			}, 1e6, 400)
+			return nil
+		},
+	}
+
+	_, body, _ := getMetrics(t, h, nil)
+	assert.Equal(t, body, strings.TrimSpace(`
+# HELP request_duration_seconds How long requests took.
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{method="GET",le="0.1"} 2
+request_duration_seconds_bucket{method="GET",le="1"} 5
+request_duration_seconds_bucket{method="GET",le="+Inf"} 6
+request_duration_seconds_sum{method="GET"} 3.25
+request_duration_seconds_count{method="GET"} 6
+request_duration_seconds_bucket{method="POST",le="0.1"} 0
+request_duration_seconds_bucket{method="POST",le="1"} 1
+request_duration_seconds_bucket{method="POST",le="+Inf"} 1
+request_duration_seconds_sum{method="POST"} 0.5
+request_duration_seconds_count{method="POST"} 1
+# HELP response_size_bytes How large responses were.
+# TYPE response_size_bytes summary
+response_size_bytes{quantile="0.5"} 1024
+response_size_bytes{quantile="0.99"} 65536.5
+response_size_bytes_sum 1e+06
+response_size_bytes_count 400
+	`)+"\n")
+
+	_, body, _ = getMetrics(t, h, http.Header{"Accept": {"application/openmetrics-text"}})
+	assert.Equal(t, body, strings.TrimSpace(`
+# HELP request_duration_seconds How long requests took.
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{method="GET",le="0.1"} 2
+request_duration_seconds_bucket{method="GET",le="1.0"} 5
+request_duration_seconds_bucket{method="GET",le="+Inf"} 6
+request_duration_seconds_sum{method="GET"} 3.25
+request_duration_seconds_count{method="GET"} 6
+request_duration_seconds_bucket{method="POST",le="0.1"} 0
+request_duration_seconds_bucket{method="POST",le="1.0"} 1
+request_duration_seconds_bucket{method="POST",le="+Inf"} 1
+request_duration_seconds_sum{method="POST"} 0.5
+request_duration_seconds_count{method="POST"} 1
+# HELP response_size_bytes How large responses were.
+# TYPE response_size_bytes summary
+response_size_bytes{quantile="0.5"} 1024.0
+response_size_bytes{quantile="0.99"} 65536.5
+response_size_bytes_sum 1e+06
+response_size_bytes_count 400
+# EOF
+	`)+"\n")
+
+	// histograms and summaries cannot be added through the generic interface
+	h.Collect = func(ctx context.Context, ms *microprom.MetricSet) error {
+		ms.Add("request_duration_seconds", "", 1.0)
+		return nil
+	}
+	msg := assert.PanicsWith[string](t, func() { getMetrics(t, h, nil) })
+	assert.Equal(t, msg, `cannot Add() to family "request_duration_seconds" of type histogram (use AddHistogram instead)`)
+
+	h.Collect = func(ctx context.Context, ms *microprom.MetricSet) error {
+		ms.AddHistogram("response_size_bytes", "", nil, 0, 0)
+		return nil
+	}
+	msg = assert.PanicsWith[string](t, func() { getMetrics(t, h, nil) })
+	assert.Equal(t, msg, `cannot AddHistogram() to family "response_size_bytes" of type summary`)
+}
+
+func TestHandlerProtobuf(t *testing.T) {
+	// NOTE: Comparisons against the output of promhttp are in `./testing/microprom`.
+	//       This only checks the wire format for a minimal example.
+
+	h := microprom.Handler{
+		Families: map[microprom.MetricFamilyName]microprom.MetricFamilyInfo{
+			"process": {
+				Type: microprom.MetricTypeInfo,
+				Help: "Info.",
+			},
+		},
+		Collect: func(ctx context.Context, ms *microprom.MetricSet) error {
+			names := microprom.NewLabelNames("version")
+			labels := ms.FormatLabels(names, "1.2.3")
+			ms.Add("process", labels, 1.0)
+			return nil
+		},
+	}
+
+	const accept = "application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited"
+	status, body, headers := getMetrics(t, h, http.Header{"Accept": {accept}})
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, headers.Get("Content-Type"), accept+"; escaping=underscores")
+	assert.Equal(t, []byte(body), []byte(""+
+		"\x36"+ // length of MetricFamily message (54 bytes)
+		"\x0a\x0cprocess_info"+ // MetricFamily.name
+		"\x12\x05Info."+ // MetricFamily.help
+		"\x18\x01"+ // MetricFamily.type = GAUGE
+		"\x22\x1d"+ // MetricFamily.metric (29 bytes)
+		"\x0a\x10"+ // Metric.label (16 bytes)
+		"\x0a\x07version"+ // LabelPair.name
+		"\x12\x051.2.3"+ // LabelPair.value
+		"\x12\x09"+ // Metric.gauge (9 bytes)
+		"\x09\x00\x00\x00\x00\x00\x00\xf0\x3f", // Gauge.value = 1.0
+	))
+}
+
+func TestHandlerStreaming(t *testing.T) {
+	h := microprom.Handler{
+		Families: map[microprom.MetricFamilyName]microprom.MetricFamilyInfo{
+			"events": {
+				Type: microprom.MetricTypeCounter,
+				Help: "Counts events that happened.",
+			},
+			"memory_usage_bytes": {
+				Type: microprom.MetricTypeGauge,
+				Help: "How much memory is currently used.",
+			},
+			"foo": {
+				Type: microprom.MetricTypeGauge,
+				Help: "This metric family will not have any collected metrics and thus go unreported.",
+			},
+		},
+		Streaming:  true,
+		SortOutput: true, // has no effect in streaming mode
+		Collect: func(ctx context.Context, ms *microprom.MetricSet) error {
+			ms.Add("memory_usage_bytes", "", 42<<20)
+			names := microprom.NewLabelNames("type")
+			ms.Add("events", ms.FormatLabels(names, "update"), 10)
+			ms.Add("events", ms.FormatLabels(names, "create"), 5)
+			return nil
+		},
+	}
+
+	// families are printed in the order in which they were added, with headers written on demand
+	status, body, _ := getMetrics(t, h, nil)
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, body, strings.TrimSpace(`
+# HELP memory_usage_bytes How much memory is currently used.
+# TYPE memory_usage_bytes gauge
+memory_usage_bytes 4.4040192e+07
+# HELP events_total Counts events that happened.
+# TYPE events_total counter
+events_total{type="update"} 10
+events_total{type="create"} 5
+	`)+"\n")
+
+	_, body, _ = getMetrics(t, h, http.Header{"Accept": {"application/openmetrics-text"}})
+	assert.Equal(t, body, strings.TrimSpace(`
+# HELP memory_usage_bytes How much memory is currently used.
+# TYPE memory_usage_bytes gauge
+memory_usage_bytes 4.4040192e+07
+# HELP events Counts events that happened.
+# TYPE events counter
+events_total{type="update"} 10.0
+events_total{type="create"} 5.0
+# EOF
+	`)+"\n")
+
+	// an error before any output was sent is reported as usual
+	h.Collect = func(ctx context.Context, ms *microprom.MetricSet) error {
+		ms.Add("memory_usage_bytes", "", 42<<20) // fits in the output buffer
+		return errors.New("kaboom")
+	}
+	status, body, _ = getMetrics(t, h, nil)
+	assert.Equal(t, status, http.StatusInternalServerError)
+	assert.Equal(t, body, "kaboom\n")
+
+	// an error after output was sent aborts the response
+	h.Collect = func(ctx context.Context, ms *microprom.MetricSet) error {
+		names := microprom.NewLabelNames("id")
+		for idx := range 1000 {
+			ms.Add("events", ms.FormatLabels(names, strconv.Itoa(idx)), 1)
+		}
+		return errors.New("kaboom")
+	}
+	err := assert.PanicsWith[error](t, func() { getMetrics(t, h, nil) })
+	assert.Equal(t, err, http.ErrAbortHandler)
+
+	// metrics of the same family must be added consecutively
+	h.Collect = func(ctx context.Context, ms *microprom.MetricSet) error {
+		ms.Add("events", "", 1)
+		ms.Add("memory_usage_bytes", "", 42<<20)
+		ms.Add("events", "", 2)
+		return nil
+	}
+	msg := assert.PanicsWith[string](t, func() { getMetrics(t, h, nil) })
+	assert.Equal(t, msg, `cannot add metric to family "events" after metrics for other families were added (in streaming mode, all metrics of a family must be added consecutively)`)
+
+	// in the protobuf format, large families are split into multiple messages
+	h.Collect = func(ctx context.Context, ms *microprom.MetricSet) error {
+		names := microprom.NewLabelNames("id")
+		for idx := range 10000 {
+			ms.Add("events", ms.FormatLabels(names, strconv.Itoa(idx)), 1)
+		}
+		return nil
+	}
+	_, body, _ = getMetrics(t, h, http.Header{"Accept": {"application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited"}})
+	var messageSizes []uint64
+	for buf := []byte(body); len(buf) > 0; {
+		size, n := binary.Uvarint(buf)
+		messageSizes = append(messageSizes, size)
+		buf = buf[n+int(size):]
+	}
+	assert.Equal(t, len(messageSizes), 4)
+	for _, size := range messageSizes {
+		assert.Equal(t, size <= 65536+32, true)
+	}
+}
+
+func TestHandlerEscaping(t *testing.T) {
+	h := microprom.Handler{
+		Families: map[microprom.MetricFamilyName]microprom.MetricFamilyInfo{
+			"http.server.active_requests": {
+				Type: microprom.MetricTypeGauge,
+				Help: "Number of active HTTP requests.",
+			},
+		},
+		Collect: func(ctx context.Context, ms *microprom.MetricSet) error {
+			names := microprom.NewLabelNames("http.method", "server")
+			ms.Add("http.server.active_requests", ms.FormatLabels(names, "GET", "api"), 3)
+			return nil
+		},
+	}
+
+	testCases := map[string]string{
+		"underscores": `
+# HELP http_server_active_requests Number of active HTTP requests.
+# TYPE http_server_active_requests gauge
+http_server_active_requests{http_method="GET",server="api"} 3
+		`,
+		"dots": `
+# HELP http_dot_server_dot_active__requests Number of active HTTP requests.
+# TYPE http_dot_server_dot_active__requests gauge
+http_dot_server_dot_active__requests{http_dot_method="GET",server="api"} 3
+		`,
+		"values": `
+# HELP U__http_2e_server_2e_active__requests Number of active HTTP requests.
+# TYPE U__http_2e_server_2e_active__requests gauge
+U__http_2e_server_2e_active__requests{U__http_2e_method="GET",server="api"} 3
+		`,
+		"allow-utf-8": `
+# HELP "http.server.active_requests" Number of active HTTP requests.
+# TYPE "http.server.active_requests" gauge
+{"http.server.active_requests","http.method"="GET",server="api"} 3
+		`,
+	}
+	for escaping, expected := range testCases {
+		accept := "text/plain; version=0.0.4; escaping=" + escaping
+		status, body, headers := getMetrics(t, h, http.Header{"Accept": {accept}})
+		assert.Equal(t, status, http.StatusOK)
+		assert.Equal(t, headers.Get("Content-Type"), "text/plain; version=0.0.4; charset=utf-8; escaping="+escaping)
+		assert.Equal(t, body, strings.TrimSpace(expected)+"\n")
+	}
+
+	// in OpenMetrics, the family name is quoted separately from the metric names
+	h.Families["http.server.requests"] = microprom.MetricFamilyInfo{
+		Type: microprom.MetricTypeCounter,
+		Help: "Number of handled HTTP requests.",
+	}
+	h.Collect = func(ctx context.Context, ms *microprom.MetricSet) error {
+		ms.Add("http.server.requests", "", 42)
+		return nil
+	}
+	_, body, _ := getMetrics(t, h, http.Header{"Accept": {"application/openmetrics-text; version=1.0.0; escaping=allow-utf-8"}})
+	assert.Equal(t, body, strings.TrimSpace(`
+# HELP "http.server.requests" Number of handled HTTP requests.
+# TYPE "http.server.requests" counter
+{"http.server.requests_total"} 42.0
+# EOF
+	`)+"\n")
+	_, body, _ = getMetrics(t, h, http.Header{"Accept": {"application/openmetrics-text; version=1.0.0; escaping=dots"}})
+	assert.Equal(t, body, strings.TrimSpace(`
+# HELP http_dot_server_dot_requests Number of handled HTTP requests.
+# TYPE http_dot_server_dot_requests counter
+http_dot_server_dot_requests_total 42.0
+# EOF
+	`)+"\n")
+
+	// in protobuf, names are not quoted
+	_, body, _ = getMetrics(t, h, http.Header{"Accept": {"application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited; escaping=allow-utf-8"}})
+	assert.Equal(t, strings.Contains(body, "\x0a\x1ahttp.server.requests_total"), true)
+}
+
+func TestHandlerWithOptions(t *testing.T) {
+	var (
+		createdAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		updatedAt = time.Date(2026, 10, 1, 12, 30, 15, 250_000_000, time.UTC)
+	)
+	h := microprom.Handler{
+		Families: map[microprom.MetricFamilyName]microprom.MetricFamilyInfo{
+			"jobs_processed": {
+				Type: microprom.MetricTypeCounter,
+				Help: "Number of processed jobs.",
+			},
+			"queue_length": {
+				Type: microprom.MetricTypeGauge,
+				Help: "Number of queued jobs.",
+			},
+		},
+		SortOutput: true,
+		Collect: func(ctx context.Context, ms *microprom.MetricSet) error {
+			names := microprom.NewLabelNames("queue")
+			ms.AddWithOptions("jobs_processed", ms.FormatLabels(names, "default"), 42, microprom.SampleOptions{
+				Timestamp: Some(updatedAt),
+				Created:   Some(createdAt),
+				Exemplar: Some(microprom.Exemplar{
+					Labels:    ms.FormatLabels(microprom.NewLabelNames("job_id"), "1234"),
+					Value:     1,
+					Timestamp: Some(updatedAt),
+				}),
+			})
+			ms.AddWithOptions("jobs_processed", ms.FormatLabels(names, "urgent"), 5, microprom.SampleOptions{
+				Created: Some(createdAt),
+			})
+			ms.AddWithOptions("queue_length", ms.FormatLabels(names, "default"), 3, microprom.SampleOptions{
+				Timestamp: Some(updatedAt),
+			})
+			ms.AddWithOptions("queue_length", ms.FormatLabels(names, "urgent"), 0, microprom.SampleOptions{})
+			return nil
+		},
+	}
+
+	// in OpenMetrics, all options are rendered
+	_, body, _ := getMetrics(t, h, http.Header{"Accept": {"application/openmetrics-text"}})
+	assert.Equal(t, body, strings.TrimSpace(`
+# HELP jobs_processed Number of processed jobs.
+# TYPE jobs_processed counter
+jobs_processed_total{queue="default"} 42.0 1790857815.25 # {job_id="1234"} 1.0 1790857815.25
+jobs_processed_created{queue="default"} 1767225600 1790857815.25
+jobs_processed_total{queue="urgent"} 5.0
+jobs_processed_created{queue="urgent"} 1767225600
+# HELP queue_length Number of queued jobs.
+# TYPE queue_length gauge
+queue_length{queue="default"} 3.0 1790857815.25
+queue_length{queue="urgent"} 0.0
+# EOF
+	`)+"\n")
+
+	// in the legacy text format, all options are dropped
+	_, body, _ = getMetrics(t, h, nil)
+	assert.Equal(t, body, strings.TrimSpace(`
+# HELP jobs_processed_total Number of processed jobs.
+# TYPE jobs_processed_total counter
+jobs_processed_total{queue="default"} 42
+jobs_processed_total{queue="urgent"} 5
+# HELP queue_length Number of queued jobs.
+# TYPE queue_length gauge
+queue_length{queue="default"} 3
+queue_length{queue="urgent"} 0
+	`)+"\n")
+
+	// options are only allowed where OpenMetrics allows them
+	h.Collect = func(ctx context.Context, ms *microprom.MetricSet) error {
+		ms.AddWithOptions("queue_length", "", 3, microprom.SampleOptions{Created: Some(createdAt)})
+		return nil
+	}
+	msg := assert.PanicsWith[string](t, func() { getMetrics(t, h, nil) })
+	assert.Equal(t, msg, `cannot AddWithOptions() to family "queue_length" of type gauge with a created timestamp (only counters have created timestamps)`)
+
+	h.Collect = func(ctx context.Context, ms *microprom.MetricSet) error {
+		ms.AddWithOptions("queue_length", "", 3, microprom.SampleOptions{Exemplar: Some(microprom.Exemplar{Value: 1})})
+		return nil
+	}
+	msg = assert.PanicsWith[string](t, func() { getMetrics(t, h, nil) })
+	assert.Equal(t, msg, `cannot AddWithOptions() to family "queue_length" of type gauge with an exemplar (only counters have exemplars)`)
+}
+
 func TestHandlerErrors(t *testing.T) {
 	h := microprom.Handler{
 		Families: map[microprom.MetricFamilyName]microprom.MetricFamilyInfo{
@@ -70,7 +467,7 @@ func TestHandlerErrors(t *testing.T) {
 	status, body, headers := getMetrics(t, h, http.Header{"Accept": {"application/json"}})
 	assert.Equal(t, status, http.StatusNotAcceptable)
 	assert.Equal(t, headers.Get("Content-Type"), "text/plain; charset=utf-8")
-	assert.Equal(t, body, "supported formats are text/plain and application/openmetrics-text\n")
+	assert.Equal(t, body, "supported formats are text/plain, application/openmetrics-text and application/vnd.google.protobuf\n")
 
 	// test error during h.Collect()
 	status, body, headers = getMetrics(t, h, nil)
@@ -79,13 +476,13 @@ func TestHandlerErrors(t *testing.T) {
 	assert.Equal(t, body, "kaboom\n")
 
 	// test panic from invalid metric family name
-	h.Families["what is this?"] = microprom.MetricFamilyInfo{
+	h.Families["what\xffis this?"] = microprom.MetricFamilyInfo{
 		Type: microprom.MetricTypeGauge,
 		Help: "invalid metric family name",
 	}
 	msg := assert.PanicsWith[string](t, func() { getMetrics(t, h, nil) })
-	assert.Equal(t, msg, `in family "what is this?": invalid family name (does not match /^[a-zA-Z_:][a-zA-Z0-9_:]*$/)`)
-	delete(h.Families, "what is this?")
+	assert.Equal(t, msg, `in family "what\xffis this?": invalid family name: "what\xffis this?" is not valid UTF-8`)
+	delete(h.Families, "what\xffis this?")
 
 	// test panic from invalid metric type
 	h.Families["invalid"] = microprom.MetricFamilyInfo{
@@ -98,13 +495,13 @@ func TestHandlerErrors(t *testing.T) {
 
 	// test panic from invalid label name
 	h.Collect = func(ctx context.Context, ms *microprom.MetricSet) error {
-		names := microprom.NewLabelNames("app:version")
+		names := microprom.NewLabelNames("app\xffversion")
 		labels := ms.FormatLabels(names, "1.2.3")
 		ms.Add("process", labels, 1.0)
 		return nil
 	}
 	msg = assert.PanicsWith[string](t, func() { getMetrics(t, h, nil) })
-	assert.Equal(t, msg, `invalid label name: "app:version"`)
+	assert.Equal(t, msg, `invalid label name: "app\xffversion" is not valid UTF-8`)
 
 	// test panic from wrong number of label values
 	h.Collect = func(ctx context.Context, ms *microprom.MetricSet) error {
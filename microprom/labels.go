@@ -11,18 +11,23 @@ import (
 // Labels holds a label set, formatted according to the text protocol of [OpenMetrics 1.0].
 // Instances are constructed through [MetricSet.FormatLabels].
 //
+// If the MetricSet uses [SyntaxProtobuf], the label set is instead encoded in the binary protobuf format.
+// Therefore, instances should not be constructed or inspected by other means.
+//
 // [OpenMetrics 1.0]: https://prometheus.io/docs/specs/om/open_metrics_spec/
 type Labels string
 
 // LabelNames holds a set of label names.
 type LabelNames struct {
-	// NOTE: This is an opaque struct because, when adding support for OpenMetrics 2.0,
-	// it will be useful to precompute escaped forms for these names where necessary.
+	// NOTE: This is an opaque struct because we precompute the rendered forms of these names for each escaping scheme.
 	//
 	// Another interesting addition might be alphabetical sorting of labels, where we
 	// would need to remember the sort order because we need to apply it to the values
 	// slice during Format().
 	names []string
+	// The names as rendered in the text formats, indexed by EscapingScheme.
+	// If all names are valid according to the legacy rules, each entry is equal to `names`.
+	rendered [len(escapingSchemeNames)][]string
 }
 
 // NewLabelNames constructs a LabelNames instance.
@@ -31,14 +36,40 @@ type LabelNames struct {
 //
 //	[a-zA-Z_][a-zA-Z0-9_]*
 //
+// Like for type [MetricFamilyName], other names are allowed as long as they are valid UTF-8 and not empty.
+// Such names are rendered according to the [EscapingScheme] of the [MetricSet].
+//
 // [OpenMetrics 1.0]: https://prometheus.io/docs/specs/om/open_metrics_spec/
 func NewLabelNames(names ...string) LabelNames {
+	allLegacy := true
 	for _, name := range names {
-		if !labelNameRx.MatchString(name) {
-			panic(fmt.Sprintf("invalid label name: %q", name))
+		err := validateName(name)
+		if err != nil {
+			panic("invalid label name: " + err.Error())
+		}
+		allLegacy = allLegacy && isLegacyName(name, false)
+	}
+
+	result := LabelNames{names: names}
+	for scheme := range result.rendered {
+		if allLegacy {
+			result.rendered[scheme] = names
+			continue
+		}
+		rendered := make([]string, len(names))
+		for idx, name := range names {
+			switch {
+			case isLegacyName(name, false):
+				rendered[idx] = name
+			case EscapingScheme(scheme) == EscapingAllowUTF8:
+				rendered[idx] = quoteName(name)
+			default:
+				rendered[idx] = escapeName(name, EscapingScheme(scheme), false)
+			}
 		}
+		result.rendered[scheme] = rendered
 	}
-	return LabelNames{names}
+	return result
 }
 
 // FormatLabels serializes a Prometheus labelset into the string format used in Prometheus text expositions.
@@ -53,7 +84,7 @@ func NewLabelNames(names ...string) LabelNames {
 func (ms *MetricSet) FormatLabels(n LabelNames, values ...string) Labels {
 	// NOTE on API structure:
 	//   - This is not part of ms.Add() to allow reusing label sets for multiple metrics.
-	//   - This is a method of MetricSet because we want to be able to depend on the negotiated syntax in the future (e.g. for escaping rules).
+	//   - This is a method of MetricSet because the result depends on the negotiated syntax and escaping scheme.
 
 	if len(n.names) != len(values) {
 		panic(fmt.Sprintf("expected %d label values, but got %d", len(n.names), len(values)))
@@ -61,13 +92,20 @@ func (ms *MetricSet) FormatLabels(n LabelNames, values ...string) Labels {
 	if len(n.names) == 0 {
 		return ""
 	}
+	if ms.syntax == SyntaxProtobuf {
+		if ms.escaping == EscapingAllowUTF8 {
+			return formatLabelsProtobuf(n.names, values)
+		}
+		return formatLabelsProtobuf(n.rendered[ms.escaping], values)
+	}
+	names := n.rendered[ms.escaping]
 
 	// estimate the perfect number of bytes for the result string to avoid reallocations
-	capacity := len(n.names) - 1 // number of "," between pairs
-	needsEscaping := make([]bool, len(n.names))
+	capacity := len(names) - 1 // number of "," between pairs
+	needsEscaping := make([]bool, len(names))
 	for idx, value := range values {
 		// base length for an encoding in the form `label="value"`
-		capacity += len(n.names[idx]) + len(value) + 3
+		capacity += len(names[idx]) + len(value) + 3
 		// some characters within `value` need escaping (TODO: this could be optimized to only iterate through `value` once)
 		toEscape := strings.Count(value, "\n") + strings.Count(value, "\"") + strings.Count(value, "\\")
 		needsEscaping[idx] = toEscape > 0
@@ -80,14 +118,12 @@ func (ms *MetricSet) FormatLabels(n LabelNames, values ...string) Labels {
 		if idx > 0 {
 			_ = b.WriteByte(',')
 		}
-		_, _ = b.WriteString(n.names[idx])
+		_, _ = b.WriteString(names[idx])
 		_ = b.WriteByte('=')
 		_ = b.WriteByte('"')
 		if needsEscaping[idx] {
 			// TODO: this could be optimized, but since this branch is unlikely in practice, I did not bother yet
-			value = strings.ReplaceAll(value, "\\", "\\\\")
-			value = strings.ReplaceAll(value, "\"", "\\\"")
-			value = strings.ReplaceAll(value, "\n", "\\n")
+			value = escapeLabelValue(value)
 		}
 		_, _ = b.WriteString(value)
 		_ = b.WriteByte('"')
@@ -14,11 +14,14 @@
 // more stable memory consumption with less intense spikes during scrapes,
 // at the cost of slightly more CPU time cost and GC pressure.
 //
-// A microprom handler produces output in the [Prometheus exposition format], matching the output of promhttp exactly;
+// A microprom handler produces output in the [Prometheus exposition format] (in either the text or protobuf variant) that is functionally equivalent to the output of promhttp,
+// except that created timestamps are not emitted in the protobuf variant;
 // thus it can be scraped by Prometheus or any other OpenTelemetry-compatible metrics collector.
 // However, because of the highly specialized focus on high-cardinality database metrics,
-// significant parts of the OTLP Stream Model (e.g. summaries, histograms, exemplars) are not implemented.
-// The only supported metric types are gauges, counters and info metrics.
+// significant parts of the OTLP Stream Model (e.g. native histograms) are not implemented.
+// The supported metric types are gauges, counters, info metrics, as well as classic histograms and summaries.
+// Sample timestamps, created timestamps and exemplars are supported for gauges, counters and info metrics (see [MetricSet.AddWithOptions]),
+// but are only rendered in the OpenMetrics 1.0 text format.
 //
 // # How to use
 //
@@ -32,7 +35,11 @@ package microprom
 
 import (
 	"fmt"
-	"regexp"
+	"math"
+	"slices"
+	"time"
+
+	. "go.xyrillian.de/gg/option"
 )
 
 // MetricFamilyInfo appears in type [Handler].
@@ -41,14 +48,10 @@ type MetricFamilyInfo struct {
 	Help string
 }
 
-var (
-	labelNameRx        = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
-	metricFamilyNameRx = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
-)
-
 func (i MetricFamilyInfo) validate(name MetricFamilyName) error {
-	if !metricFamilyNameRx.MatchString(string(name)) {
-		return fmt.Errorf("in family %q: invalid family name (does not match /%s/)", name, metricFamilyNameRx.String())
+	err := validateName(string(name))
+	if err != nil {
+		return fmt.Errorf("in family %q: invalid family name: %w", name, err)
 	}
 	if i.Type >= MetricType(len(metricTypeSuffixes)) {
 		return fmt.Errorf("in family %q: invalid value for microprom.MetricType: %d", name, i.Type)
@@ -62,9 +65,9 @@ func (i MetricFamilyInfo) validate(name MetricFamilyName) error {
 //
 //	^[a-zA-Z_:][a-zA-Z0-9_:]*$
 //
-// Package microprom does not implement escaping at the moment;
-// metric family names not matching this pattern are invalid and will cause a panic.
-// This restriction may be lifted in a future version.
+// Newer versions of Prometheus also allow arbitrary UTF-8 strings, e.g. OpenTelemetry-style names like "http.server.duration".
+// Such names are rendered according to the [EscapingScheme] of the [MetricSet].
+// Empty names and names that are not valid UTF-8 are invalid and will cause a panic.
 //
 // [OpenMetrics 1.0]: https://prometheus.io/docs/specs/om/open_metrics_spec/
 type MetricFamilyName string
@@ -93,29 +96,112 @@ const (
 	//
 	// For this metric type, the metric name is formed by appending "_info" to the metric family name.
 	MetricTypeInfo
+
+	// MetricTypeHistogram is used for distributions of observed values that have been sorted into buckets,
+	// e.g. request durations.
+	//
+	// Metrics of this type must be added with [MetricSet.AddHistogram] instead of [MetricSet.Add].
+	// Each histogram is rendered as multiple series, whose names are formed by appending
+	// "_bucket", "_sum" and "_count" to the metric family name.
+	MetricTypeHistogram
+
+	// MetricTypeSummary is used for distributions of observed values that have been summarized into quantiles,
+	// e.g. the median and 99th percentile of request durations.
+	//
+	// Metrics of this type must be added with [MetricSet.AddSummary] instead of [MetricSet.Add].
+	// Each summary is rendered as multiple series: one series per quantile with the same name as the metric family,
+	// as well as two more series whose names are formed by appending "_sum" and "_count" to the metric family name.
+	MetricTypeSummary
 )
 
 var (
-	metricTypeNames    = []string{"gauge", "counter", "info"}
-	metricTypeSuffixes = []string{"", "_total", "_info"}
+	metricTypeNames    = []string{"gauge", "counter", "info", "histogram", "summary"}
+	metricTypeSuffixes = []string{"", "_total", "_info", "", ""}
 )
 
 // MetricSet holds a set of metrics.
 type MetricSet struct {
-	syntax  Syntax
-	metrics map[MetricFamilyName][]metric
+	syntax   Syntax
+	escaping EscapingScheme
+	families map[MetricFamilyName]MetricFamilyInfo
+	metrics  map[MetricFamilyName][]metric
+	stream   *metricStream // only set in streaming mode (see Handler.Streaming)
 }
 
 type metric struct {
 	labels Labels
-	value  float64
+	value  float64 // for histograms and summaries, this is the sum
+	dist   *distribution
+	opts   *SampleOptions // only set by AddWithOptions() in SyntaxOpenMetricsV1
+}
+
+// distribution holds the additional data for metrics of type histogram or summary.
+type distribution struct {
+	buckets   []Bucket
+	quantiles []Quantile
+	count     uint64
+}
+
+// Bucket is a bucket of a histogram. It appears in the arguments of [MetricSet.AddHistogram].
+type Bucket struct {
+	// The inclusive upper bound of this bucket (rendered as the "le" label).
+	UpperBound float64
+	// The number of observations that were less than or equal to UpperBound.
+	// As usual for Prometheus histograms, this count is cumulative: It includes the observations counted by all buckets with a lower UpperBound.
+	CumulativeCount uint64
+}
+
+// Quantile is a quantile of a summary. It appears in the arguments of [MetricSet.AddSummary].
+type Quantile struct {
+	// The quantile, as a number between 0 and 1 (rendered as the "quantile" label).
+	Quantile float64
+	// The value of this quantile among the observed values.
+	Value float64
+}
+
+// SampleOptions holds additional data for a metric that is only rendered in some exposition formats.
+// It appears in the arguments of [MetricSet.AddWithOptions].
+//
+// All fields are only rendered in [SyntaxOpenMetricsV1].
+// In the other syntaxes, they are silently dropped, and the metric is rendered as if it had been given to [MetricSet.Add].
+type SampleOptions struct {
+	// The time at which the value was observed.
+	// If None, the scraper will assume that the value was observed at scrape time, which is the usual behavior.
+	// Timestamps are rendered with millisecond precision.
+	Timestamp Option[time.Time]
+	// The time at which a counter started counting from zero (rendered as the "_created" series).
+	// This may only be set for metric families of type [MetricTypeCounter].
+	Created Option[time.Time]
+	// An example of an observation that contributed to the value of a counter, e.g. a request with a specific trace ID.
+	// This may only be set for metric families of type [MetricTypeCounter].
+	Exemplar Option[Exemplar]
+}
+
+// Exemplar is an example of an observation. It appears in type [SampleOptions].
+type Exemplar struct {
+	// The labels identifying this observation (e.g. a trace ID), as returned by [MetricSet.FormatLabels].
+	// OpenMetrics 1.0 limits the total length of all label names and values to 128 characters.
+	Labels Labels
+	// The observed value, e.g. the increment of the counter.
+	Value float64
+	// The time at which the observation was made, if known.
+	Timestamp Option[time.Time]
 }
 
 // NewMetricSet constructs an initially empty [MetricSet] that accepts metrics for the given metric families.
+// Names are rendered using [EscapingUnderscores].
 func NewMetricSet(syntax Syntax, families map[MetricFamilyName]MetricFamilyInfo) *MetricSet {
-	if syntax > SyntaxOpenMetricsV1 {
+	return NewMetricSetWithEscaping(syntax, EscapingUnderscores, families)
+}
+
+// NewMetricSetWithEscaping is like [NewMetricSet], but uses the given escaping scheme for rendering names.
+func NewMetricSetWithEscaping(syntax Syntax, escaping EscapingScheme, families map[MetricFamilyName]MetricFamilyInfo) *MetricSet {
+	if syntax > SyntaxProtobuf {
 		panic(fmt.Sprintf("unknown value for Syntax: %d", syntax))
 	}
+	if escaping > EscapingValues {
+		panic(fmt.Sprintf("unknown value for EscapingScheme: %d", escaping))
+	}
 	m := make(map[MetricFamilyName][]metric, len(families))
 	for name, family := range families {
 		err := family.validate(name)
@@ -125,29 +211,120 @@ func NewMetricSet(syntax Syntax, families map[MetricFamilyName]MetricFamilyInfo)
 		}
 		m[name] = nil
 	}
-	return &MetricSet{syntax, m}
+	return &MetricSet{syntax, escaping, families, m, nil}
 }
 
 // Add adds a metric to the MetricSet.
 //
 // The name must be of a metric family that was declared during [NewMetricSet], otherwise Add will panic.
 // The metric name will be derived according to the rules documented on the respective [MetricType].
+//
+// In streaming mode (see [Handler]), the metric is written into the response immediately.
+// In this mode, all metrics of the same family must be added consecutively, otherwise Add will panic.
+//
+// Metrics of type [MetricTypeHistogram] or [MetricTypeSummary] cannot be added with this method.
+// Use [MetricSet.AddHistogram] or [MetricSet.AddSummary] instead.
 func (ms *MetricSet) Add(name MetricFamilyName, labels Labels, value float64) {
-	_, ok := ms.metrics[name]
+	ms.checkSimpleType("Add", name)
+	ms.add(name, metric{labels, value, nil, nil})
+}
+
+// AddWithOptions is like [MetricSet.Add], but additionally takes a sample timestamp, created timestamp and exemplar.
+// These are only rendered in [SyntaxOpenMetricsV1]. See documentation on type [SampleOptions] for details.
+//
+// This is intended for metrics derived from database contents, where it is known when a value was last updated,
+// or when a counter was initialized.
+func (ms *MetricSet) AddWithOptions(name MetricFamilyName, labels Labels, value float64, opts SampleOptions) {
+	metricType := ms.checkSimpleType("AddWithOptions", name)
+	if metricType != MetricTypeCounter {
+		if opts.Created.IsSome() {
+			panic(fmt.Sprintf("cannot AddWithOptions() to family %q of type %s with a created timestamp (only counters have created timestamps)", name, metricTypeNames[metricType]))
+		}
+		if opts.Exemplar.IsSome() {
+			panic(fmt.Sprintf("cannot AddWithOptions() to family %q of type %s with an exemplar (only counters have exemplars)", name, metricTypeNames[metricType]))
+		}
+	}
+
+	if ms.syntax == SyntaxOpenMetricsV1 {
+		ms.add(name, metric{labels, value, nil, &opts})
+	} else {
+		ms.add(name, metric{labels, value, nil, nil})
+	}
+}
+
+// AddHistogram adds a metric to the MetricSet, for a metric family of type [MetricTypeHistogram].
+// This is intended for histograms that are computed at scrape time, e.g. from a SQL query with GROUP BY.
+//
+// The buckets must be sorted by UpperBound, and their CumulativeCount must not decrease from one bucket to the next.
+// The final bucket with UpperBound = +Inf may be omitted, in which case it will be added with CumulativeCount = count.
+// The sum and count are the sum and number of all observations, respectively.
+//
+// The name must be of a metric family of type [MetricTypeHistogram] that was declared during [NewMetricSet], otherwise AddHistogram will panic.
+// The label set must not contain the "le" label, since that label will be added to each bucket.
+func (ms *MetricSet) AddHistogram(name MetricFamilyName, labels Labels, buckets []Bucket, sum float64, count uint64) {
+	if ms.familyType(name) != MetricTypeHistogram {
+		panic(fmt.Sprintf("cannot AddHistogram() to family %q of type %s", name, metricTypeNames[ms.familyType(name)]))
+	}
+	if len(buckets) == 0 || !math.IsInf(buckets[len(buckets)-1].UpperBound, +1) {
+		buckets = append(slices.Clip(buckets), Bucket{UpperBound: math.Inf(+1), CumulativeCount: count})
+	}
+	ms.add(name, metric{labels, sum, &distribution{buckets: buckets, count: count}, nil})
+}
+
+// AddSummary adds a metric to the MetricSet, for a metric family of type [MetricTypeSummary].
+// This is intended for summaries that are computed at scrape time, e.g. from a SQL query using percentile_cont().
+//
+// The quantiles should be sorted by their Quantile field.
+// The sum and count are the sum and number of all observations, respectively.
+//
+// The name must be of a metric family of type [MetricTypeSummary] that was declared during [NewMetricSet], otherwise AddSummary will panic.
+// The label set must not contain the "quantile" label, since that label will be added to each quantile.
+func (ms *MetricSet) AddSummary(name MetricFamilyName, labels Labels, quantiles []Quantile, sum float64, count uint64) {
+	if ms.familyType(name) != MetricTypeSummary {
+		panic(fmt.Sprintf("cannot AddSummary() to family %q of type %s", name, metricTypeNames[ms.familyType(name)]))
+	}
+	ms.add(name, metric{labels, sum, &distribution{quantiles: quantiles, count: count}, nil})
+}
+
+func (ms *MetricSet) add(name MetricFamilyName, m metric) {
+	if ms.stream != nil {
+		ms.stream.Write(ms, name, ms.families[name], m)
+	} else {
+		ms.metrics[name] = append(ms.metrics[name], m)
+	}
+}
+
+// checkSimpleType panics if the given metric family has a type that requires a different method than Add() and its variants.
+func (ms *MetricSet) checkSimpleType(method string, name MetricFamilyName) MetricType {
+	metricType := ms.familyType(name)
+	switch metricType {
+	case MetricTypeHistogram:
+		panic(fmt.Sprintf("cannot %s() to family %q of type histogram (use AddHistogram instead)", method, name))
+	case MetricTypeSummary:
+		panic(fmt.Sprintf("cannot %s() to family %q of type summary (use AddSummary instead)", method, name))
+	default:
+		return metricType
+	}
+}
+
+func (ms *MetricSet) familyType(name MetricFamilyName) MetricType {
+	family, ok := ms.families[name]
 	if !ok {
 		panic("no such family: " + string(name))
 	}
-	ms.metrics[name] = append(ms.metrics[name], metric{labels, value})
+	return family.Type
 }
 
 // Syntax is an enum, defining which exposition format will be used by [MetricSet].
 //
 //   - SyntaxPrometheusLegacy corresponds to the [Prometheus Text Format].
 //   - SyntaxOpenMetricsV1 corresponds to the [OpenMetrics 1.0] text format
+//   - SyntaxProtobuf corresponds to the [Prometheus protobuf format].
 //   - Additional formats may be added in the future (e.g. OpenMetrics 2.0, once it is stabilized).
 //
 // [Prometheus Text Format]: https://prometheus.io/docs/instrumenting/exposition_formats/
 // [OpenMetrics 1.0]: https://prometheus.io/docs/specs/om/open_metrics_spec/
+// [Prometheus protobuf format]: https://prometheus.io/docs/instrumenting/exposition_formats/#protobuf-format
 type Syntax uint
 
 const (
@@ -155,4 +332,6 @@ const (
 	SyntaxPrometheusLegacy Syntax = iota
 	// SyntaxOpenMetricsV1 corresponds to the OpenMetrics 1.0 text format.
 	SyntaxOpenMetricsV1
+	// SyntaxProtobuf corresponds to the Prometheus protobuf format, i.e. a stream of varint-delimited MetricFamily messages.
+	SyntaxProtobuf
 )
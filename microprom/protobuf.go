@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package microprom
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// This file implements the Prometheus protobuf exposition format, i.e. a stream of varint-delimited MetricFamily messages.
+// The message definitions can be found in <https://github.com/prometheus/client_model/blob/master/io/prometheus/client/metrics.proto>.
+// We encode these messages by hand to avoid depending on a protobuf library.
+
+// Field numbers and wire types from metrics.proto.
+const (
+	pbWireVarint  = 0
+	pbWireFixed64 = 1
+	pbWireBytes   = 2
+
+	pbLabelPairName  = 1
+	pbLabelPairValue = 2
+
+	pbMetricFamilyName   = 1
+	pbMetricFamilyHelp   = 2
+	pbMetricFamilyType   = 3
+	pbMetricFamilyMetric = 4
+
+	pbMetricLabel     = 1
+	pbMetricGauge     = 2
+	pbMetricCounter   = 3
+	pbMetricSummary   = 4
+	pbMetricHistogram = 7
+
+	pbGaugeValue   = 1
+	pbCounterValue = 1
+
+	pbSummarySampleCount = 1
+	pbSummarySampleSum   = 2
+	pbSummaryQuantile    = 3
+	pbQuantileQuantile   = 1
+	pbQuantileValue      = 2
+
+	pbHistogramSampleCount  = 1
+	pbHistogramSampleSum    = 2
+	pbHistogramBucket       = 3
+	pbBucketCumulativeCount = 1
+	pbBucketUpperBound      = 2
+)
+
+// Values of the MetricType enum from metrics.proto, indexed by our own MetricType.
+// Info metrics do not have a representation in the protobuf format, so they are reported as gauges (like in client_golang).
+var pbMetricTypes = []uint64{
+	MetricTypeGauge:     1, // GAUGE
+	MetricTypeCounter:   0, // COUNTER
+	MetricTypeInfo:      1, // GAUGE
+	MetricTypeHistogram: 4, // HISTOGRAM
+	MetricTypeSummary:   2, // SUMMARY
+}
+
+func pbAppendTag(buf []byte, field, wireType uint64) []byte {
+	return binary.AppendUvarint(buf, field<<3|wireType)
+}
+
+func pbAppendVarint(buf []byte, field, value uint64) []byte {
+	buf = pbAppendTag(buf, field, pbWireVarint)
+	return binary.AppendUvarint(buf, value)
+}
+
+func pbAppendDouble(buf []byte, field uint64, value float64) []byte {
+	buf = pbAppendTag(buf, field, pbWireFixed64)
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(value))
+}
+
+func pbAppendBytes[S string | []byte](buf []byte, field uint64, value S) []byte {
+	buf = pbAppendTag(buf, field, pbWireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+// formatLabelsProtobuf implements FormatLabels() for SyntaxProtobuf.
+// The label set is encoded as a sequence of LabelPair messages in the "label" field of the Metric message,
+// so that it can be copied into the Metric message verbatim.
+func formatLabelsProtobuf(names, values []string) Labels {
+	var (
+		buf       []byte
+		labelPair []byte
+	)
+	for idx, name := range names {
+		labelPair = pbAppendBytes(labelPair[:0], pbLabelPairName, name)
+		labelPair = pbAppendBytes(labelPair, pbLabelPairValue, values[idx])
+		buf = pbAppendBytes(buf, pbMetricLabel, labelPair)
+	}
+	return Labels(buf)
+}
+
+// protobufWriter holds reusable buffers for encoding MetricFamily messages.
+type protobufWriter struct {
+	family     []byte
+	hasMetrics bool
+	metric     []byte
+	inner      []byte
+	item       []byte
+}
+
+// StartFamily begins encoding a MetricFamily message.
+func (pw *protobufWriter) StartFamily(metricName string, info MetricFamilyInfo) {
+	pw.family = pbAppendBytes(pw.family[:0], pbMetricFamilyName, metricName)
+	if info.Help != "" {
+		pw.family = pbAppendBytes(pw.family, pbMetricFamilyHelp, info.Help)
+	}
+	pw.family = pbAppendVarint(pw.family, pbMetricFamilyType, pbMetricTypes[info.Type])
+	pw.hasMetrics = false
+}
+
+// AddMetric appends a Metric message to the MetricFamily message that was begun by StartFamily.
+func (pw *protobufWriter) AddMetric(info MetricFamilyInfo, m metric) {
+	pw.metric = append(pw.metric[:0], m.labels...)
+	switch info.Type {
+	case MetricTypeHistogram:
+		pw.inner = pbAppendVarint(pw.inner[:0], pbHistogramSampleCount, m.dist.count)
+		pw.inner = pbAppendDouble(pw.inner, pbHistogramSampleSum, m.value)
+		for _, b := range m.dist.buckets {
+			// the +Inf bucket is implicit in the protobuf format
+			if math.IsInf(b.UpperBound, +1) {
+				continue
+			}
+			pw.item = pbAppendVarint(pw.item[:0], pbBucketCumulativeCount, b.CumulativeCount)
+			pw.item = pbAppendDouble(pw.item, pbBucketUpperBound, b.UpperBound)
+			pw.inner = pbAppendBytes(pw.inner, pbHistogramBucket, pw.item)
+		}
+		pw.metric = pbAppendBytes(pw.metric, pbMetricHistogram, pw.inner)
+	case MetricTypeSummary:
+		pw.inner = pbAppendVarint(pw.inner[:0], pbSummarySampleCount, m.dist.count)
+		pw.inner = pbAppendDouble(pw.inner, pbSummarySampleSum, m.value)
+		for _, q := range m.dist.quantiles {
+			pw.item = pbAppendDouble(pw.item[:0], pbQuantileQuantile, q.Quantile)
+			pw.item = pbAppendDouble(pw.item, pbQuantileValue, q.Value)
+			pw.inner = pbAppendBytes(pw.inner, pbSummaryQuantile, pw.item)
+		}
+		pw.metric = pbAppendBytes(pw.metric, pbMetricSummary, pw.inner)
+	case MetricTypeCounter:
+		pw.inner = pbAppendDouble(pw.inner[:0], pbCounterValue, m.value)
+		pw.metric = pbAppendBytes(pw.metric, pbMetricCounter, pw.inner)
+	default:
+		pw.inner = pbAppendDouble(pw.inner[:0], pbGaugeValue, m.value)
+		pw.metric = pbAppendBytes(pw.metric, pbMetricGauge, pw.inner)
+	}
+	pw.family = pbAppendBytes(pw.family, pbMetricFamilyMetric, pw.metric)
+	pw.hasMetrics = true
+}
+
+// FinishFamily writes the MetricFamily message that was begun by StartFamily, preceded by its length.
+// If no metrics were added since StartFamily, nothing is written.
+//
+// Like for the text formats, write errors are expected to be caught by the caller when flushing the buffered writer.
+func (pw *protobufWriter) FinishFamily(w io.Writer) {
+	if !pw.hasMetrics {
+		return
+	}
+	// reuse pw.inner to hold the length prefix
+	pw.inner = binary.AppendUvarint(pw.inner[:0], uint64(len(pw.family)))
+	_, _ = w.Write(pw.inner)
+	_, _ = w.Write(pw.family)
+	pw.hasMetrics = false
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package microprom
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// protobufChunkSize is the size above which a MetricFamily message will be split when streaming in the protobuf format.
+const protobufChunkSize = 64 << 10
+
+// metricStream appears in type MetricSet. It holds the state for the streaming mode of type Handler.
+type metricStream struct {
+	w       io.Writer
+	current MetricFamilyName // the family of the most recently written metric
+	names   familyNames      // the rendered names for the current family
+	seen    map[MetricFamilyName]bool
+	pw      protobufWriter // only used for SyntaxProtobuf
+}
+
+// Write writes a single metric, preceded by a family header if this is the first metric of its family.
+func (s *metricStream) Write(ms *MetricSet, name MetricFamilyName, info MetricFamilyInfo, m metric) {
+	if name != s.current {
+		if s.seen[name] {
+			// this is fine to panic because it will only blow up in case of gross API misuse
+			panic(fmt.Sprintf("cannot add metric to family %q after metrics for other families were added (in streaming mode, all metrics of a family must be added consecutively)", name))
+		}
+		s.FinishFamily()
+		s.seen[name] = true
+		s.current = name
+		s.names = ms.familyNames(name, info)
+
+		if ms.syntax == SyntaxProtobuf {
+			s.pw.StartFamily(s.names.metric, info)
+		} else {
+			printFamilyHeader(s.w, s.names, info)
+		}
+	}
+
+	if ms.syntax == SyntaxProtobuf {
+		// a MetricFamily message needs to be buffered entirely since it is prefixed by its length,
+		// so we limit memory usage by splitting large families into multiple messages
+		s.pw.AddMetric(info, m)
+		if len(s.pw.family) >= protobufChunkSize {
+			s.pw.FinishFamily(s.w)
+			s.pw.StartFamily(s.names.metric, info)
+		}
+	} else {
+		printMetric(s.w, ms.syntax, s.names, info, m)
+	}
+}
+
+// FinishFamily writes out any buffered data for the current family.
+func (s *metricStream) FinishFamily() {
+	s.pw.FinishFamily(s.w)
+}
+
+// serveStreaming implements ServeHTTP for Handler.Streaming = true.
+func (h Handler) serveStreaming(w http.ResponseWriter, r *http.Request, ms *MetricSet) {
+	cw := &countingWriter{inner: w}
+	bw := bufio.NewWriter(cw)
+	syntax := ms.syntax
+	ms.metrics = nil // not used in streaming mode
+	ms.stream = &metricStream{w: bw, seen: make(map[MetricFamilyName]bool)}
+
+	err := h.Collect(r.Context(), ms)
+	if err != nil {
+		if cw.bytesWritten == 0 {
+			// since nothing has been sent yet, we can still report the error normally
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// The status code and part of the response body have already been sent, so the error cannot be reported in the status code anymore.
+		// To ensure that the scraper sees a failed scrape instead of a successful scrape with incomplete data,
+		// we abort the response, which either closes the connection (HTTP/1.x) or resets the stream (HTTP/2).
+		// Before that, we leave a comment for humans that are looking at the response with e.g. curl.
+		if syntax != SyntaxProtobuf {
+			fmt.Fprintf(bw, "# collect error: %s\n", err.Error())
+			_ = bw.Flush()
+		}
+		panic(http.ErrAbortHandler)
+	}
+
+	ms.stream.FinishFamily()
+	h.finishResponse(w, bw, syntax)
+}
+
+// countingWriter is an io.Writer that counts how many bytes were written into it.
+type countingWriter struct {
+	inner        io.Writer
+	bytesWritten int64
+}
+
+// Write implements the io.Writer interface.
+func (cw *countingWriter) Write(buf []byte) (int, error) {
+	n, err := cw.inner.Write(buf)
+	cw.bytesWritten += int64(n)
+	return n, err
+}
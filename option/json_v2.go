@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build go1.27 && goexperiment.jsonv2
+
+package option
+
+import (
+	"encoding/json/jsontext"
+	jsonv2 "encoding/json/v2"
+)
+
+// static assertion that the respective interfaces are implemented
+var (
+	_ jsonv2.MarshalerTo     = Option[bool]{}
+	_ jsonv2.UnmarshalerFrom = &Option[bool]{}
+)
+
+// MarshalJSONTo implements the [jsonv2.MarshalerTo] interface.
+// It behaves like MarshalJSON, but writes directly into the encoder instead of allocating an intermediate buffer.
+func (o Option[T]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	if o.isSome {
+		return jsonv2.MarshalEncode(enc, o.value)
+	} else {
+		return enc.WriteToken(jsontext.Null)
+	}
+}
+
+// UnmarshalJSONFrom implements the [jsonv2.UnmarshalerFrom] interface.
+// It behaves like UnmarshalJSON, but reads directly from the decoder instead of allocating an intermediate buffer.
+func (o *Option[T]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	if dec.PeekKind() == 'n' {
+		_, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		*o = None[T]()
+		return nil
+	}
+
+	var value T
+	err := jsonv2.UnmarshalDecode(dec, &value)
+	if err != nil {
+		return err
+	}
+	*o = Some(value)
+	return nil
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build go1.27 && goexperiment.jsonv2
+
+package option_test
+
+import (
+	"encoding/json"
+	jsonv2 "encoding/json/v2"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	. "go.xyrillian.de/gg/option"
+)
+
+func TestMarshalAndUnmarshalJSONv2(t *testing.T) {
+	type inner struct {
+		Name  Option[string]   `json:"name"`
+		Tags  Option[[]string] `json:"tags"`
+		Count Option[int]      `json:"count,omitzero"`
+	}
+	type payload struct {
+		N1 Option[int]          `json:"n1"`
+		N2 Option[inner]        `json:"n2,omitzero"`
+		N3 Option[Option[int]]  `json:"n3"`
+		S1 Option[int]          `json:"s1"`
+		S2 Option[inner]        `json:"s2,omitzero"`
+		S3 Option[[]inner]      `json:"s3"`
+		S4 Option[map[int]bool] `json:"s4"`
+	}
+	original := payload{
+		N1: None[int](),
+		N2: None[inner](),
+		N3: None[Option[int]](),
+		S1: Some(1),
+		S2: Some(inner{Name: Some("foo"), Tags: None[[]string]()}),
+		S3: Some([]inner{{Tags: Some([]string{"bar"}), Count: Some(3)}}),
+		S4: Some(map[int]bool{42: true}),
+	}
+
+	// encoding/json/v2 must produce the same result as encoding/json
+	bufV1, err := json.Marshal(original)
+	assert.Equal(t, err, nil)
+	bufV2, err := jsonv2.Marshal(original)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(bufV2), string(bufV1))
+	assert.Equal(t, string(bufV2), `{"n1":null,"n3":null,"s1":1,"s2":{"name":"foo","tags":null},"s3":[{"name":null,"tags":["bar"],"count":3}],"s4":{"42":true}}`)
+
+	var decoded payload
+	err = jsonv2.Unmarshal(bufV2, &decoded)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, decoded, original)
+
+	// errors from the contained type are propagated
+	var o Option[int]
+	err = jsonv2.Unmarshal([]byte(`"foo"`), &o)
+	assert.Equal(t, err != nil, true)
+	assert.Equal(t, o, None[int]())
+
+	// in encoding/json/v2, "omitempty" also omits Some values that encode into empty JSON values
+	type omitting struct {
+		A Option[string] `json:"a,omitempty"`
+		B Option[string] `json:"b,omitempty"`
+		C Option[string] `json:"c,omitzero"`
+		D Option[string] `json:"d,omitzero"`
+	}
+	buf, err := jsonv2.Marshal(omitting{None[string](), Some(""), None[string](), Some("")})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(buf), `{"d":""}`)
+}
@@ -27,6 +27,14 @@
 //
 // Marshaling into and from JSON using encoding/json is supported, but the "omitempty" flag does not work.
 // You must use the "omitzero" flag to get the same effect, but note that this flag is only supported by Go 1.24 and newer.
+// When encoding/json/v2 is available (on Go 1.27 and newer with the jsonv2 experiment enabled), Option also implements its streaming interfaces.
+// The result of marshaling and unmarshaling is the same as for the legacy interfaces, but nested values are processed without intermediate allocations.
+// In encoding/json/v2, "omitzero" omits None values, whereas "omitempty" omits None values as well as values like Some("") or Some([]int{}).
+//
+// Marshaling into and from text (as used e.g. for JSON map keys, XML attributes or TOML) is supported if T
+// implements [encoding.TextMarshaler] and [encoding.TextUnmarshaler], or if T is a boolean, numeric or string type.
+// The empty string represents None.
+// Option also implements [flag.Value], so it can be used with [flag.Var] to declare flags without a default value.
 //
 // # How to replace pointer types with Option types
 //
@@ -111,9 +119,14 @@ package option // import "go.xyrillian.de/gg/option"
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"iter"
+	"reflect"
+	"strconv"
+	"time"
 )
 
 // Option is a type that contains either one or no instances of T.
@@ -304,6 +317,10 @@ var (
 	_ driver.Valuer    = Option[bool]{}
 	_ json.Marshaler   = Option[bool]{}
 	_ json.Unmarshaler = &Option[bool]{}
+
+	_ encoding.TextMarshaler   = Option[bool]{}
+	_ encoding.TextUnmarshaler = &Option[bool]{}
+	_ flag.Value               = &Option[bool]{}
 )
 
 // Format implements the [fmt.Formatter] interface.
@@ -329,18 +346,30 @@ type yamlMarshaler interface {
 }
 
 // Scan implements the [sql.Scanner] interface.
+//
+// NULL is scanned into None. All other values are scanned into Some, using the first applicable rule:
+//
+//   - If *T implements [sql.Scanner], its Scan method is used.
+//   - If the value is textual (a string or []byte) and *T implements [json.Unmarshaler], its UnmarshalJSON method is used.
+//     Otherwise, if T is a struct or map type, the value is unmarshaled with [json.Unmarshal].
+//     This allows for scanning JSON and JSONB columns into arbitrary types.
+//   - If the value is textual and T is a slice type (except for []byte), the value is parsed as a one-dimensional Postgres array,
+//     e.g. `{foo,NULL,"bar baz"}`, and each element is scanned into the element type following the same rules as for this method.
+//     Elements that are NULL can only be scanned into element types that implement [sql.Scanner] (such as Option itself).
+//     If the value is not a Postgres array (i.e. does not start with an opening brace), it is unmarshaled with [json.Unmarshal] instead.
+//   - Otherwise, the value is converted in the same way as [sql.Rows.Scan] would convert it into T.
 func (o *Option[T]) Scan(src any) error {
-	var data sql.Null[T]
-	err := data.Scan(src)
-	if err != nil {
-		return err
+	if src == nil {
+		*o = None[T]()
+		return nil
 	}
 
-	if data.Valid {
-		*o = Some(data.V)
-	} else {
-		*o = None[T]()
+	var value T
+	err := scanInto(&value, src)
+	if err != nil {
+		return err
 	}
+	*o = Some(value)
 	return nil
 }
 
@@ -373,6 +402,126 @@ func (o *Option[T]) UnmarshalJSON(buf []byte) error {
 	return nil
 }
 
+// MarshalText implements the [encoding.TextMarshaler] interface.
+//
+// None is marshaled into the empty string.
+// Some is marshaled using the MarshalText method of T, if there is one.
+// Otherwise, booleans and numbers are formatted with package strconv, and strings are marshaled as-is.
+// As an exception, [time.Duration] values are formatted with [time.Duration.String].
+// For all other types, an error is returned.
+//
+// Note that if the contained value is marshaled into the empty string (e.g. in the case of Some("")),
+// the result is indistinguishable from None, and will be unmarshaled as None by UnmarshalText.
+func (o Option[T]) MarshalText() ([]byte, error) {
+	if !o.isSome {
+		return nil, nil
+	}
+	if m, ok := any(o.value).(encoding.TextMarshaler); ok {
+		return m.MarshalText()
+	}
+	if d, ok := any(o.value).(time.Duration); ok {
+		return []byte(d.String()), nil
+	}
+
+	v := reflect.ValueOf(o.value)
+	switch v.Kind() {
+	case reflect.String:
+		return []byte(v.String()), nil
+	case reflect.Bool:
+		return strconv.AppendBool(nil, v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(nil, v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.AppendUint(nil, v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.AppendFloat(nil, v.Float(), 'g', -1, v.Type().Bits()), nil
+	default:
+		return nil, fmt.Errorf("cannot marshal %T into text", o.value)
+	}
+}
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+//
+// The empty string is unmarshaled into None.
+// All other inputs are unmarshaled into Some, using the inverse of the rules described for MarshalText.
+func (o *Option[T]) UnmarshalText(buf []byte) error {
+	if len(buf) == 0 {
+		*o = None[T]()
+		return nil
+	}
+
+	var value T
+	err := unmarshalTextInto(&value, string(buf))
+	if err != nil {
+		return err
+	}
+	*o = Some(value)
+	return nil
+}
+
+// unmarshalTextInto implements the non-empty case of UnmarshalText. The target must be a pointer.
+func unmarshalTextInto(target any, input string) error {
+	if u, ok := target.(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(input))
+	}
+	if d, ok := target.(*time.Duration); ok {
+		var err error
+		*d, err = time.ParseDuration(input)
+		return err
+	}
+
+	v := reflect.ValueOf(target).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(input)
+		return nil
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(input)
+		v.SetBool(parsed)
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(input, 10, v.Type().Bits())
+		v.SetInt(parsed)
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		parsed, err := strconv.ParseUint(input, 10, v.Type().Bits())
+		v.SetUint(parsed)
+		return err
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(input, v.Type().Bits())
+		v.SetFloat(parsed)
+		return err
+	default:
+		return fmt.Errorf("cannot unmarshal text into %s", v.Type())
+	}
+}
+
+// Set implements the [flag.Value] interface. It behaves like UnmarshalText.
+//
+// When an Option is declared as a flag with [flag.Var], it will be None if the flag is not given on the command line.
+// Note that the empty string is unmarshaled into None, so "-flag=" is equivalent to not giving the flag at all.
+func (o *Option[T]) Set(input string) error {
+	return o.UnmarshalText([]byte(input))
+}
+
+// String implements the [flag.Value] interface.
+// It behaves like MarshalText, except that it returns the empty string instead of an error if the contained value cannot be marshaled into text.
+//
+// Note that package fmt uses Format instead of this method.
+func (o Option[T]) String() string {
+	buf, err := o.MarshalText()
+	if err != nil {
+		return ""
+	}
+	return string(buf)
+}
+
+// IsBoolFlag is used by package flag to recognize boolean flags. It returns true if T is a boolean type.
+// This allows an Option[bool] flag to be given as "-flag" instead of "-flag=true", like for flags declared with [flag.Bool].
+func (o Option[T]) IsBoolFlag() bool {
+	return reflect.TypeFor[T]().Kind() == reflect.Bool
+}
+
 // MarshalYAML implements the yaml.Marshaler interface from gopkg.in/yaml.v2 and v3 as well as go.yaml.in/yaml/v3.
 func (o Option[T]) MarshalYAML() (any, error) {
 	if o.isSome {
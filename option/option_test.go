@@ -4,10 +4,17 @@
 package option_test
 
 import (
+	"database/sql"
+	"encoding"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
+	"net"
+	"reflect"
 	"slices"
 	"testing"
+	"time"
 
 	"go.xyrillian.de/gg/assert"
 	. "go.xyrillian.de/gg/option"
@@ -186,9 +193,112 @@ func TestUnmarshalSQL(t *testing.T) {
 	assert.Equal(t, err, nil)
 	assert.Equal(t, o2, Some("hello"))
 
-	var o3 Option[struct{}]
-	err = o3.Scan("hello")
-	assert.Equal(t, err.Error(), "unsupported Scan, storing driver.Value type string into type *struct {}")
+	var o3 Option[int64]
+	err = o3.Scan([]byte("hello"))
+	assert.ErrEqual(t, err, `converting driver.Value type []uint8 ("hello") to a int64: invalid syntax`)
+
+	var o4 Option[chan int]
+	err = o4.Scan("hello")
+	assert.ErrEqual(t, err, "unsupported Scan, storing driver.Value type string into type *chan int")
+
+	// if *T implements sql.Scanner, it is used for all non-NULL values
+	var o5 Option[sql.NullString]
+	err = o5.Scan("hello")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o5, Some(sql.NullString{String: "hello", Valid: true}))
+
+	// JSON values can be scanned into structs and maps, as well as into types that implement json.Unmarshaler
+	type payload struct {
+		Name Option[string] `json:"name"`
+	}
+	var o6 Option[payload]
+	err = o6.Scan([]byte(`{"name":"foo"}`))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o6, Some(payload{Some("foo")}))
+
+	var o7 Option[map[string]int]
+	err = o7.Scan(`{"foo":42}`)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o7, Some(map[string]int{"foo": 42}))
+
+	var o8 Option[json.RawMessage]
+	err = o8.Scan([]byte(`[1, 2]`))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o8, Some(json.RawMessage(`[1, 2]`)))
+
+	var o9 Option[[]int]
+	err = o9.Scan([]byte(`[1, 2]`))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o9, Some([]int{1, 2}))
+
+	// []byte is not treated as an array
+	var o10 Option[[]byte]
+	err = o10.Scan([]byte("{1,2}"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o10, Some([]byte("{1,2}")))
+}
+
+func TestUnmarshalSQLArray(t *testing.T) {
+	var o1 Option[[]string]
+	err := o1.Scan(nil)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o1, None[[]string]())
+
+	err = o1.Scan([]byte("{}"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o1, Some([]string{}))
+
+	err = o1.Scan([]byte(`{foo,"bar baz","with \"quotes\" and \\backslashes",NULLABLE,"NULL"}`))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o1, Some([]string{"foo", "bar baz", `with "quotes" and \backslashes`, "NULLABLE", "NULL"}))
+
+	var o2 Option[[]int64]
+	err = o2.Scan("{1,-2,3}")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o2, Some([]int64{1, -2, 3}))
+
+	var o3 Option[[]bool]
+	err = o3.Scan([]byte("{t,f}"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o3, Some([]bool{true, false}))
+
+	// NULL elements can be scanned into element types that implement sql.Scanner
+	var o4 Option[[]Option[float64]]
+	err = o4.Scan([]byte("{1.5,NULL,null}"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o4, Some([]Option[float64]{Some(1.5), None[float64](), None[float64]()}))
+
+	var o5 Option[[]sql.NullString]
+	err = o5.Scan([]byte(`{NULL,"foo"}`))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o5, Some([]sql.NullString{{}, {String: "foo", Valid: true}}))
+
+	// elements of JSONB arrays are unmarshaled from JSON
+	var o6 Option[[]map[string]int]
+	err = o6.Scan([]byte(`{"{\"foo\": 1}","{}"}`))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o6, Some([]map[string]int{{"foo": 1}, {}}))
+
+	// error cases
+	var o7 Option[[]string]
+	err = o7.Scan([]byte("{foo,NULL}"))
+	assert.ErrEqual(t, err, `cannot scan element 1 of "{foo,NULL}" into []string: cannot scan NULL into string`)
+	err = o7.Scan([]byte(`{{foo},{bar}}`))
+	assert.ErrEqual(t, err, `cannot scan "{{foo},{bar}}" into []string: multi-dimensional arrays are not supported`)
+	err = o7.Scan([]byte(`{"foo}`))
+	assert.ErrEqual(t, err, `cannot scan "{\"foo}" into []string: unterminated quoted element`)
+	err = o7.Scan([]byte(`{"foo"bar}`))
+	assert.ErrEqual(t, err, `cannot scan "{\"foo\"bar}" into []string: unexpected "b" after element`)
+	assert.Equal(t, o7, None[[]string]())
+
+	var o8 Option[[]uint8]
+	err = o8.Scan("{1,2}")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o8, Some([]uint8("{1,2}")))
+
+	var o9 Option[[]int16]
+	err = o9.Scan("{1,70000}")
+	assert.ErrEqual(t, err, `cannot scan element 1 of "{1,70000}" into []int16: strconv.ParseInt: parsing "70000": value out of range`)
 }
 
 func TestMarshalAndUnmarshalJSON(t *testing.T) {
@@ -217,3 +327,123 @@ func TestMarshalAndUnmarshalJSON(t *testing.T) {
 	assert.Equal(t, err, nil)
 	assert.Equal(t, decoded, original)
 }
+
+func BenchmarkMarshalAndUnmarshalJSON(b *testing.B) {
+	// NOTE: Run this benchmark with and without GOEXPERIMENT=nojsonv2 to compare the streaming implementation against the legacy implementation.
+	type inner struct {
+		Name  Option[string] `json:"name"`
+		Count Option[int]    `json:"count,omitzero"`
+	}
+	type payload struct {
+		ID    Option[int]            `json:"id"`
+		Inner Option[inner]          `json:"inner"`
+		Items Option[[]inner]        `json:"items"`
+		Extra Option[Option[string]] `json:"extra"`
+	}
+	value := payload{
+		ID:    Some(42),
+		Inner: Some(inner{Name: Some("foo"), Count: Some(1)}),
+		Items: Some([]inner{{Name: Some("bar")}, {Name: None[string](), Count: Some(2)}}),
+		Extra: Some(Some("baz")),
+	}
+	buf, err := json.Marshal(value)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("op=Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_, _ = json.Marshal(value)
+		}
+	})
+	b.Run("op=Unmarshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			var decoded payload
+			_ = json.Unmarshal(buf, &decoded)
+		}
+	})
+}
+
+func TestMarshalAndUnmarshalText(t *testing.T) {
+	testCases := []struct {
+		Value    any // must be a pointer to an Option
+		Expected string
+	}{
+		{&Option[int]{}, ""},
+		{ptr(Some(-42)), "-42"},
+		{ptr(Some(uint8(255))), "255"},
+		{ptr(Some(true)), "true"},
+		{ptr(Some(0.25)), "0.25"},
+		{ptr(Some("hello")), "hello"},
+		{ptr(Some(90 * time.Second)), "1m30s"},
+		{ptr(Some(net.IPv4(127, 0, 0, 1))), "127.0.0.1"},
+	}
+	for _, tc := range testCases {
+		buf, err := tc.Value.(encoding.TextMarshaler).MarshalText()
+		assert.Equal(t, err, nil)
+		assert.Equal(t, string(buf), tc.Expected)
+
+		// roundtrip through a fresh instance of the same type
+		decoded := reflect.New(reflect.TypeOf(tc.Value).Elem()).Interface()
+		err = decoded.(encoding.TextUnmarshaler).UnmarshalText(buf)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, decoded, tc.Value)
+	}
+
+	// Some with an empty text form cannot be distinguished from None
+	buf, err := Some("").MarshalText()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(buf), "")
+
+	_, err = Some(struct{}{}).MarshalText()
+	assert.ErrEqual(t, err, "cannot marshal struct {} into text")
+
+	var o1 Option[int8]
+	assert.ErrEqual(t, o1.UnmarshalText([]byte("300")), `strconv.ParseInt: parsing "300": value out of range`)
+	var o2 Option[struct{}]
+	assert.ErrEqual(t, o2.UnmarshalText([]byte("hello")), "cannot unmarshal text into struct {}")
+
+	// Option can be used as a map key in JSON
+	buf, err = json.Marshal(map[Option[int]]string{None[int](): "none", Some(42): "some"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(buf), `{"":"none","42":"some"}`)
+}
+
+func TestFlagValue(t *testing.T) {
+	var (
+		count   Option[int]
+		timeout Option[time.Duration]
+	)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&count, "count", "")
+	fs.Var(&timeout, "timeout", "")
+
+	err := fs.Parse([]string{"-timeout", "5s"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, count, None[int]())
+	assert.Equal(t, timeout, Some(5*time.Second))
+	assert.Equal(t, fs.Lookup("timeout").Value.String(), "5s")
+
+	fs.SetOutput(io.Discard)
+	err = fs.Parse([]string{"-count", "many"})
+	assert.ErrEqual(t, err, `invalid value "many" for flag -count: strconv.ParseInt: parsing "many": invalid syntax`)
+
+	// Option[bool] can be used as a boolean flag without an explicit value
+	var verbose, dryRun, debug Option[bool]
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&verbose, "verbose", "")
+	fs.Var(&dryRun, "dry-run", "")
+	fs.Var(&debug, "debug", "")
+	err = fs.Parse([]string{"-verbose", "-dry-run=false"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, verbose, Some(true))
+	assert.Equal(t, dryRun, Some(false))
+	assert.Equal(t, debug, None[bool]())
+	assert.Equal(t, count.IsBoolFlag(), false)
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
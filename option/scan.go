@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// scanInto implements the non-NULL case of Option.Scan().
+func scanInto[T any](target *T, src any) error {
+	if scanner, ok := any(target).(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+
+	var text string
+	switch src := src.(type) {
+	case string:
+		text = src
+	case []byte:
+		text = string(src)
+	default:
+		return scanByConversion(target, src)
+	}
+
+	switch {
+	case isJSONTarget(target):
+		return json.Unmarshal([]byte(text), target)
+	case isArrayTarget(target):
+		if !strings.HasPrefix(text, "{") {
+			return json.Unmarshal([]byte(text), target)
+		}
+		return scanArray(reflect.ValueOf(target).Elem(), text)
+	default:
+		return scanByConversion(target, src)
+	}
+}
+
+// scanByConversion scans a non-NULL value using the conversion rules of package database/sql.
+func scanByConversion[T any](target *T, src any) error {
+	var data sql.Null[T]
+	err := data.Scan(src)
+	if err != nil {
+		return err
+	}
+	*target = data.V
+	return nil
+}
+
+// isJSONTarget returns whether textual values shall be scanned into the given pointer by unmarshaling them as JSON.
+func isJSONTarget(target any) bool {
+	if _, ok := target.(json.Unmarshaler); ok {
+		return true
+	}
+	switch reflect.TypeOf(target).Elem().Kind() {
+	case reflect.Struct, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+// isArrayTarget returns whether textual values shall be scanned into the given pointer by parsing them as Postgres arrays.
+func isArrayTarget(target any) bool {
+	t := reflect.TypeOf(target).Elem()
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+}
+
+// scanArray parses a one-dimensional Postgres array literal into the given slice.
+func scanArray(target reflect.Value, text string) error {
+	elements, err := parseArrayLiteral(text)
+	if err != nil {
+		return fmt.Errorf("cannot scan %q into %s: %w", text, target.Type(), err)
+	}
+
+	result := reflect.MakeSlice(target.Type(), len(elements), len(elements))
+	for idx, element := range elements {
+		ptr := result.Index(idx).Addr().Interface()
+		scanner, isScanner := ptr.(sql.Scanner)
+		switch {
+		case isScanner && element.isNull:
+			err = scanner.Scan(nil)
+		case isScanner:
+			err = scanner.Scan(element.value)
+		case element.isNull:
+			err = errors.New("cannot scan NULL into " + target.Type().Elem().String())
+		case isJSONTarget(ptr):
+			err = json.Unmarshal([]byte(element.value), ptr)
+		default:
+			err = unmarshalTextInto(ptr, element.value)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot scan element %d of %q into %s: %w", idx, text, target.Type(), err)
+		}
+	}
+	target.Set(result)
+	return nil
+}
+
+type arrayElement struct {
+	value  string
+	isNull bool
+}
+
+// parseArrayLiteral splits a one-dimensional Postgres array literal like `{foo,NULL,"bar baz"}` into its elements.
+func parseArrayLiteral(text string) ([]arrayElement, error) {
+	inner, ok := strings.CutPrefix(text, "{")
+	if ok {
+		inner, ok = strings.CutSuffix(inner, "}")
+	}
+	if !ok {
+		return nil, errors.New("not an array literal")
+	}
+	if inner == "" {
+		return []arrayElement{}, nil
+	}
+
+	var (
+		result []arrayElement
+		buf    strings.Builder
+	)
+	for {
+		buf.Reset()
+		if strings.HasPrefix(inner, `"`) {
+			// quoted element: read until the closing quote, resolving backslash escapes
+			idx := 1
+			for {
+				if idx >= len(inner) {
+					return nil, errors.New("unterminated quoted element")
+				}
+				c := inner[idx]
+				idx++
+				if c == '"' {
+					break
+				}
+				if c == '\\' {
+					if idx >= len(inner) {
+						return nil, errors.New("unterminated quoted element")
+					}
+					c = inner[idx]
+					idx++
+				}
+				buf.WriteByte(c)
+			}
+			result = append(result, arrayElement{value: buf.String()})
+			inner = inner[idx:]
+		} else {
+			// unquoted element: read until the next delimiter
+			idx := strings.IndexByte(inner, ',')
+			if idx == -1 {
+				idx = len(inner)
+			}
+			value := strings.TrimSpace(inner[:idx])
+			switch {
+			case strings.ContainsAny(value, `{}"`):
+				return nil, errors.New("multi-dimensional arrays are not supported")
+			case value == "":
+				return nil, errors.New("empty unquoted element")
+			case strings.EqualFold(value, "NULL"):
+				result = append(result, arrayElement{isNull: true})
+			default:
+				result = append(result, arrayElement{value: value})
+			}
+			inner = inner[idx:]
+		}
+
+		if inner == "" {
+			return result, nil
+		}
+		inner, ok = strings.CutPrefix(inner, ",")
+		if !ok {
+			return nil, fmt.Errorf("unexpected %q after element", inner[:1])
+		}
+	}
+}
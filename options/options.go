@@ -7,12 +7,117 @@ package options // import "go.xyrillian.de/gg/options"
 
 import (
 	"cmp"
+	"fmt"
+	"iter"
+	"os"
 
 	. "go.xyrillian.de/gg/option"
 )
 
 // NOTE: Keep functions sorted by name.
 
+// AndThen applies the given function to the value contained in o, if there is one, and returns its result.
+// This is like [Map], but for functions that may themselves return None.
+func AndThen[T, U any](o Option[T], mapping func(T) Option[U]) Option[U] {
+	if t, ok := o.Unpack(); ok {
+		return mapping(t)
+	} else {
+		return None[U]()
+	}
+}
+
+// Collect collects all values from the given sequence into a slice.
+// If any element of the sequence is None, None is returned instead.
+// If the sequence is empty, Some(nil) is returned.
+//
+// Iteration stops at the first None element.
+func Collect[T any](seq iter.Seq[Option[T]]) Option[[]T] {
+	var result []T
+	for o := range seq {
+		value, ok := o.Unpack()
+		if !ok {
+			return None[[]T]()
+		}
+		result = append(result, value)
+	}
+	return Some(result)
+}
+
+// FilterMap applies the given function to each element of the given sequence,
+// and returns a sequence of those function results that are not None.
+func FilterMap[T, U any](seq iter.Seq[T], mapping func(T) Option[U]) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for t := range seq {
+			if u, ok := mapping(t).Unpack(); ok {
+				if !yield(u) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Find returns the first element of the given sequence that matches the predicate, or None if there is no such element.
+//
+// See package go.xyrillian.de/gg/is for pre-made predicates.
+func Find[T any](seq iter.Seq[T], predicate func(T) bool) Option[T] {
+	for value := range seq {
+		if predicate(value) {
+			return Some(value)
+		}
+	}
+	return None[T]()
+}
+
+// First returns the first element of the given sequence, or None if the sequence is empty.
+func First[T any](seq iter.Seq[T]) Option[T] {
+	for value := range seq {
+		return Some(value)
+	}
+	return None[T]()
+}
+
+// Flatten returns a sequence of the values contained in the given sequence, skipping all None elements.
+// For example, this turns a sequence of Some(1), None, Some(2) into a sequence of 1, 2.
+func Flatten[T any](seq iter.Seq[Option[T]]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for o := range seq {
+			if value, ok := o.Unpack(); ok {
+				if !yield(value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// FlattenNested removes one level of nesting from an Option.
+// Some(Some(x)) becomes Some(x), and both Some(None) and None become None.
+//
+// (This function is not called Flatten() because that name refers to the function operating on sequences of options.)
+func FlattenNested[T any](o Option[Option[T]]) Option[T] {
+	return o.UnwrapOr(None[T]())
+}
+
+// FromEnv reads the environment variable with the given key and parses its value like [Option.UnmarshalText].
+// If the variable is unset or empty, None is returned.
+//
+//	timeout, err := options.FromEnv[time.Duration]("MYAPP_TIMEOUT")
+//	if err != nil {
+//		return err
+//	}
+//	client.Timeout = timeout.UnwrapOr(30 * time.Second)
+//
+// Parse errors are returned with the variable name prepended, e.g. `cannot parse MYAPP_TIMEOUT: ...`.
+func FromEnv[T any](key string) (Option[T], error) {
+	var result Option[T]
+	err := result.UnmarshalText([]byte(os.Getenv(key)))
+	if err != nil {
+		return None[T](), fmt.Errorf("cannot parse %s: %w", key, err)
+	}
+	return result, nil
+}
+
 // FromPointer converts a *T into an Option[T].
 func FromPointer[T any](value *T) Option[T] {
 	if value == nil {
@@ -39,6 +144,26 @@ func Map[T, U any](o Option[T], mapping func(T) U) Option[U] {
 	}
 }
 
+// MapOr applies the given function to the value contained in o, if there is one.
+// If o is empty, the given fallback value is returned instead.
+func MapOr[T, U any](o Option[T], fallback U, mapping func(T) U) U {
+	if t, ok := o.Unpack(); ok {
+		return mapping(t)
+	} else {
+		return fallback
+	}
+}
+
+// MapOrElse applies the given function to the value contained in o, if there is one.
+// If o is empty, the given fallback function is used to produce the return value instead.
+func MapOrElse[T, U any](o Option[T], fallback func() U, mapping func(T) U) U {
+	if t, ok := o.Unpack(); ok {
+		return mapping(t)
+	} else {
+		return fallback()
+	}
+}
+
 // Max returns the largest of its input values, while disregarding None values.
 // If there are no Some values, None is returned.
 func Max[T cmp.Ordered](inputs ...Option[T]) Option[T] {
@@ -80,3 +205,45 @@ func Min[T cmp.Ordered](inputs ...Option[T]) Option[T] {
 		return None[T]()
 	}
 }
+
+// OkOr converts o into the usual pair of return values for fallible operations.
+// If o is empty, the given error is returned alongside the zero value of T.
+//
+//	func (c Config) DatabaseURL() (string, error) {
+//		return options.OkOr(c.DatabaseURL, errors.New("no database URL configured"))
+//	}
+func OkOr[T any](o Option[T], err error) (T, error) {
+	if t, ok := o.Unpack(); ok {
+		return t, nil
+	} else {
+		return t, err
+	}
+}
+
+// Pair holds two values. It appears in the signatures of [Zip] and [Unzip].
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Unzip is the inverse of [Zip]: If o contains a Pair, its elements are returned as separate options.
+// Otherwise, two empty options are returned.
+func Unzip[A, B any](o Option[Pair[A, B]]) (Option[A], Option[B]) {
+	if p, ok := o.Unpack(); ok {
+		return Some(p.First), Some(p.Second)
+	} else {
+		return None[A](), None[B]()
+	}
+}
+
+// Zip combines two options into one.
+// If both a and b contain values, a Pair of those values is returned. Otherwise, None is returned.
+func Zip[A, B any](a Option[A], b Option[B]) Option[Pair[A, B]] {
+	aValue, aOK := a.Unpack()
+	bValue, bOK := b.Unpack()
+	if aOK && bOK {
+		return Some(Pair[A, B]{aValue, bValue})
+	} else {
+		return None[Pair[A, B]]()
+	}
+}
@@ -4,13 +4,107 @@
 package options
 
 import (
+	"errors"
+	"slices"
 	"strconv"
 	"testing"
+	"time"
 
 	"go.xyrillian.de/gg/assert"
 	. "go.xyrillian.de/gg/option"
 )
 
+func TestAndThen(t *testing.T) {
+	half := func(x int) Option[int] {
+		if x%2 == 0 {
+			return Some(x / 2)
+		}
+		return None[int]()
+	}
+	assert.Equal(t, AndThen(None[int](), half), None[int]())
+	assert.Equal(t, AndThen(Some(41), half), None[int]())
+	assert.Equal(t, AndThen(Some(42), half), Some(21))
+}
+
+func TestCollect(t *testing.T) {
+	assert.Equal(t, Collect(slices.Values([]Option[int]{})), Some([]int(nil)))
+	assert.Equal(t, Collect(slices.Values([]Option[int]{Some(1), Some(2)})), Some([]int{1, 2}))
+	assert.Equal(t, Collect(slices.Values([]Option[int]{Some(1), None[int](), Some(2)})), None[[]int]())
+}
+
+func TestFilterMap(t *testing.T) {
+	parse := func(s string) Option[int] {
+		value, err := strconv.Atoi(s)
+		if err != nil {
+			return None[int]()
+		}
+		return Some(value)
+	}
+	seq := FilterMap(slices.Values([]string{"1", "foo", "2", "", "3"}), parse)
+	assert.Equal(t, slices.Collect(seq), []int{1, 2, 3})
+
+	// check early exit
+	for value := range seq {
+		assert.Equal(t, value, 1)
+		break
+	}
+}
+
+func TestFind(t *testing.T) {
+	isEven := func(x int) bool { return x%2 == 0 }
+	assert.Equal(t, Find(slices.Values([]int{}), isEven), None[int]())
+	assert.Equal(t, Find(slices.Values([]int{1, 3, 5}), isEven), None[int]())
+	assert.Equal(t, Find(slices.Values([]int{1, 4, 5, 6}), isEven), Some(4))
+}
+
+func TestFirst(t *testing.T) {
+	assert.Equal(t, First(slices.Values([]int{})), None[int]())
+	assert.Equal(t, First(slices.Values([]int{5, 23})), Some(5))
+}
+
+func TestFlatten(t *testing.T) {
+	seq := Flatten(slices.Values([]Option[int]{Some(1), None[int](), Some(2), None[int](), Some(3)}))
+	assert.Equal(t, slices.Collect(seq), []int{1, 2, 3})
+
+	// check early exit
+	for value := range seq {
+		assert.Equal(t, value, 1)
+		break
+	}
+}
+
+func TestFlattenNested(t *testing.T) {
+	assert.Equal(t, FlattenNested(None[Option[int]]()), None[int]())
+	assert.Equal(t, FlattenNested(Some(None[int]())), None[int]())
+	assert.Equal(t, FlattenNested(Some(Some(42))), Some(42))
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("GG_TEST_EMPTY", "")
+	t.Setenv("GG_TEST_INT", "42")
+	t.Setenv("GG_TEST_DURATION", "5m")
+
+	o1, err := FromEnv[int]("GG_TEST_UNSET")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o1, None[int]())
+
+	o2, err := FromEnv[int]("GG_TEST_EMPTY")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o2, None[int]())
+
+	o3, err := FromEnv[int]("GG_TEST_INT")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o3, Some(42))
+
+	o4, err := FromEnv[time.Duration]("GG_TEST_DURATION")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, o4, Some(5*time.Minute))
+
+	o5, err := FromEnv[bool]("GG_TEST_INT")
+	assert.ErrEqual(t, err, `cannot parse GG_TEST_INT: strconv.ParseBool: parsing "42": invalid syntax`)
+	assert.Equal(t, o5, None[bool]())
+}
+
 func TestFromPointer(t *testing.T) {
 	assert.Equal(t, FromPointer[int](nil), None[int]())
 	assert.Equal(t, FromPointer(new(int(42))), Some(42))
@@ -27,6 +121,17 @@ func TestMap(t *testing.T) {
 	assert.Equal(t, Map(Some(42), strconv.Itoa), Some("42"))
 }
 
+func TestMapOr(t *testing.T) {
+	assert.Equal(t, MapOr(None[int](), "none", strconv.Itoa), "none")
+	assert.Equal(t, MapOr(Some(42), "none", strconv.Itoa), "42")
+}
+
+func TestMapOrElse(t *testing.T) {
+	fallback := func() string { return "none" }
+	assert.Equal(t, MapOrElse(None[int](), fallback, strconv.Itoa), "none")
+	assert.Equal(t, MapOrElse(Some(42), fallback, strconv.Itoa), "42")
+}
+
 func TestMax(t *testing.T) {
 	assert.Equal(t, Max[int](), None[int]())
 	assert.Equal(t, Max(None[int]()), None[int]())
@@ -66,3 +171,27 @@ func TestMin(t *testing.T) {
 	assert.Equal(t, Min(Some(23), None[int](), Some(5)), Some(5))
 	assert.Equal(t, Min(Some(23), Some(5), None[int]()), Some(5))
 }
+
+func TestOkOr(t *testing.T) {
+	errNone := errors.New("no value")
+	value, err := OkOr(None[int](), errNone)
+	assert.Equal(t, value, 0)
+	assert.Equal(t, err, errNone)
+	value, err = OkOr(Some(42), errNone)
+	assert.Equal(t, value, 42)
+	assert.Equal(t, err, nil)
+}
+
+func TestZipAndUnzip(t *testing.T) {
+	assert.Equal(t, Zip(None[int](), None[string]()), None[Pair[int, string]]())
+	assert.Equal(t, Zip(Some(42), None[string]()), None[Pair[int, string]]())
+	assert.Equal(t, Zip(None[int](), Some("foo")), None[Pair[int, string]]())
+	assert.Equal(t, Zip(Some(42), Some("foo")), Some(Pair[int, string]{42, "foo"}))
+
+	a, b := Unzip(None[Pair[int, string]]())
+	assert.Equal(t, a, None[int]())
+	assert.Equal(t, b, None[string]())
+	a, b = Unzip(Some(Pair[int, string]{42, "foo"}))
+	assert.Equal(t, a, Some(42))
+	assert.Equal(t, b, Some("foo"))
+}
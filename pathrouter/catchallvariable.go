@@ -5,6 +5,7 @@ package pathrouter
 
 import (
 	"strings"
+	"sync/atomic"
 
 	. "go.xyrillian.de/gg/option"
 )
@@ -14,12 +15,24 @@ import (
 // such that the remainder is accepted by the next matcher.
 // At least one element must be collected into vars[name].
 //
-// CatchAllVariable() may appear at any point within the routing tree,
-// but it may not contain another CatchAllVariable() anywhere within it.
+// CatchAllVariable() may appear at any point within the routing tree, including within another CatchAllVariable().
+// If multiple ways of splitting the path are acceptable, the leftmost CatchAllVariable() collects as many path elements as possible.
+// For example, given this matcher:
+//
+//	m := pr.Element("diff", pr.CatchAllVariable("left", pr.Element("with", pr.CatchAllVariable("right", pr.Handlers(...)))))
+//
+// the request path "/diff/a/with/b/with/c" will be matched with vars["left"] = "a/with/b" and vars["right"] = "c".
+//
+// Routes with multiple CatchAllVariable() are matched in polynomial time:
+// Each CatchAllVariable() remembers which subpaths were already declined by the next matcher,
+// so that they will not be tried again when an enclosing CatchAllVariable() tries the next way of splitting the path.
 func CatchAllVariable(name string, matcher Matcher) Matcher {
 	return catchAllVariable(name, matcher.downcast())
 }
 
+// catchAllCounter is used to give each CatchAllVariable() a unique ID, for use as a key in matchState.declinedSubpaths.
+var catchAllCounter atomic.Uint64
+
 func catchAllVariable(name string, matcher realMatcher) Matcher {
 	// NOTE: The specific behavior of CatchAllVariable() is why this package exists in the first place.
 	//       I wanted to replace gorilla/mux with something more performance in Keppel,
@@ -27,26 +40,33 @@ func catchAllVariable(name string, matcher realMatcher) Matcher {
 	//       like the OCI Distribution API requires (e.g. "/v2/*repo/manifests/:reference" with "repo" being a full path).
 
 	innerMinLength := matcher.minLength
-	innerMaxLength, ok := matcher.maxLength.Unpack()
-	if !ok {
-		panic("matcher within CatchAllVariable() may not accept unlimited path lengths")
-	}
+	innerMaxLength, isBounded := matcher.maxLength.Unpack()
 
-	accept := func(path []string, vars map[string]string) HandlerFunc {
-		for length := innerMinLength; length <= innerMaxLength; length++ {
-			if length > len(path) {
-				break
-			}
+	// If the inner matcher has bounded length, it cannot contain another CatchAllVariable(),
+	// so the amount of work done by accept() is bounded without needing to remember declined subpaths.
+	id := catchAllCounter.Add(1)
+	remember := !isBounded
+
+	accept := func(path []string, s *matchState) HandlerFunc {
+		// NOTE: Since at least one path element must be caught, `length < len(path)` instead of `length <= len(path)`.
+		for length := innerMinLength; length < len(path) && (!isBounded || length <= innerMaxLength); length++ {
 			caughtPath, subpath := path[0:len(path)-length], path[len(path)-length:]
-			if len(caughtPath) == 0 {
+
+			attempt := subpathAttempt{id, length}
+			if remember && s.declinedSubpaths[attempt] {
 				continue
 			}
-
-			handlerFunc := matcher.accept(subpath, vars)
+			handlerFunc := matcher.accept(subpath, s)
 			if handlerFunc == nil {
+				if remember {
+					if s.declinedSubpaths == nil {
+						s.declinedSubpaths = make(map[subpathAttempt]bool)
+					}
+					s.declinedSubpaths[attempt] = true
+				}
 				continue
 			}
-			vars[name] = pathUnescape(strings.Join(caughtPath, "/"))
+			s.vars[name] = pathUnescape(strings.Join(caughtPath, "/"))
 			return handlerFunc
 		}
 		return nil
@@ -55,6 +75,7 @@ func catchAllVariable(name string, matcher realMatcher) Matcher {
 	return realMatcher{
 		minLength: innerMinLength + 1,
 		maxLength: None[int](),
+		node:      node{kind: nodeCatchAllVariable, value: name, children: []realMatcher{matcher}},
 		accept:    accept,
 	}
 }
@@ -15,6 +15,10 @@ import (
 //
 // Choice is used to specify different matchers for different subpaths,
 // as illustrated in the example in the package docstring.
+//
+// Choice has been optimized for large numbers of options:
+// When multiple consecutive options are [Element] matchers, they are checked in one step using a lookup table,
+// instead of comparing the path element to each of their values in turn.
 func Choice(matchers ...Matcher) Matcher {
 	downcasted := make([]realMatcher, len(matchers))
 	for idx, matcher := range matchers {
@@ -45,13 +49,18 @@ func choice(matchers []realMatcher) Matcher {
 		maxLength = options.Max(maxLengths...)
 	}
 
+	branches := compileChoice(matchers)
 	return realMatcher{
 		minLength: slices.Min(minLengths),
 		maxLength: maxLength,
-		accept: func(path []string, vars map[string]string) HandlerFunc {
-			for _, m := range matchers {
-				hf := m.accept(path, vars)
+		node:      node{kind: nodeChoice, children: matchers},
+		accept: func(path []string, s *matchState) HandlerFunc {
+			for _, b := range branches {
+				hf := b.accept(path, s)
 				if hf != nil {
+					if b.index >= 0 {
+						s.traceChoice(b.index)
+					}
 					return hf
 				}
 			}
@@ -59,3 +68,89 @@ func choice(matchers []realMatcher) Matcher {
 		},
 	}
 }
+
+// Runs of consecutive Element() options within Choice() will be checked using a lookup table if they are at least this long.
+// Below this size, comparing each value is faster than a map lookup.
+const minElementsForLookupTable = 4
+
+// choiceBranch appears in func compileChoice.
+type choiceBranch struct {
+	accept acceptFunc
+	// The index of the option within the flattened list of options (for use with matchState.traceChoice),
+	// or -1 if accept() records this index by itself.
+	index int
+}
+
+// compileChoice returns a list of accept() functions that is equivalent to calling accept() on each of the given matchers in order.
+// The result is optimized by replacing runs of consecutive Element() matchers with a single lookup table.
+func compileChoice(matchers []realMatcher) []choiceBranch {
+	// nested Choice() matchers can be flattened since first-match-wins is associative
+	flattened := flattenChoices(matchers)
+
+	var result []choiceBranch
+	for offset := 0; offset < len(flattened); {
+		runLength := 0
+		for offset+runLength < len(flattened) && flattened[offset+runLength].node.kind == nodeElement {
+			runLength++
+		}
+		if runLength >= minElementsForLookupTable {
+			result = append(result, choiceBranch{buildLookupTable(flattened[offset:offset+runLength], offset), -1})
+			offset += runLength
+		} else {
+			result = append(result, choiceBranch{flattened[offset].accept, offset})
+			offset++
+		}
+	}
+	return result
+}
+
+func flattenChoices(matchers []realMatcher) []realMatcher {
+	result := make([]realMatcher, 0, len(matchers))
+	for _, m := range matchers {
+		if m.node.kind == nodeChoice {
+			result = append(result, flattenChoices(m.node.children)...)
+		} else {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// buildLookupTable builds an accept() function that is equivalent to calling accept() on each of the given Element() matchers in order.
+// The first of these matchers has the given index within the flattened list of options.
+func buildLookupTable(elements []realMatcher, offset int) acceptFunc {
+	// if multiple elements have the same value, all of their next matchers need to be tried in order
+	type entry struct {
+		matcher realMatcher
+		index   int
+	}
+	table := make(map[string][]entry, len(elements))
+	for idx, m := range elements {
+		table[m.node.value] = append(table[m.node.value], entry{m.node.children[0], offset + idx})
+	}
+
+	return func(path []string, s *matchState) HandlerFunc {
+		if len(path) == 0 {
+			return nil
+		}
+		if s.foldCase {
+			// the lookup table cannot be used for case-insensitive matching
+			for idx, m := range elements {
+				hf := m.accept(path, s)
+				if hf != nil {
+					s.traceChoice(offset + idx)
+					return hf
+				}
+			}
+			return nil
+		}
+		for _, e := range table[path[0]] {
+			hf := e.matcher.accept(path[1:], s)
+			if hf != nil {
+				s.traceChoice(e.index)
+				return hf
+			}
+		}
+		return nil
+	}
+}
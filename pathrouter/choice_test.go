@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	pr "go.xyrillian.de/gg/pathrouter"
+)
+
+func TestChoiceWithLookupTable(t *testing.T) {
+	h := func(msg string) pr.Matcher {
+		return pr.Handlers(pr.ByMethod{
+			http.MethodGet: func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+				fmt.Fprint(w, msg)
+			},
+		})
+	}
+
+	// This Choice() contains enough consecutive Element() options to be compiled into a lookup table,
+	// including nested Choice() options that get flattened and duplicate values.
+	m := pr.Choice(
+		pr.Element("a", h("a")),
+		pr.Element("b", h("b")),
+		pr.Choice(
+			pr.Element("c", pr.Element("first", h("c/first"))),
+			pr.Element("d", h("d")),
+		),
+		pr.Element("c", pr.Choice(
+			pr.Element("first", h("unreachable")),
+			pr.Element("second", h("c/second")),
+		)),
+		pr.Variable("var", pr.Element("x", h("var/x"))),
+		pr.Element("e", h("e")),
+		pr.Element("a", pr.Element("x", h("a/x (unreachable)"))),
+		pr.Element("/", h("root")),
+	)
+	check := func(path, expected string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "http://localhost"+path, http.NoBody)
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, req)
+		assert.Equal(t, fmt.Sprintf("%d: %s", rec.Code, strings.TrimSpace(rec.Body.String())), expected)
+	}
+
+	check("/a", "200: a")
+	check("/b", "200: b")
+	check("/c", "404: 404 page not found")
+	check("/c/first", "200: c/first")
+	check("/c/second", "200: c/second")
+	check("/d", "200: d")
+	check("/e", "200: e")
+	check("/", "200: root")
+	check("/f", "404: 404 page not found")
+	check("/f/x", "200: var/x")
+	check("/a/x", "200: var/x") // first match wins even across the lookup table boundary
+}
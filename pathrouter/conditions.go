@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Host is a [Matcher] that accepts requests only if the hostname of the request matches the given pattern,
+// and if the next matcher accepts the request.
+// It does not consume any path elements.
+//
+// The pattern is a hostname, in which any label may be replaced by a variable name in curly braces, e.g. "{tenant}.api.example.com".
+// In this example, the request hostname "acme.api.example.com" would be accepted and have "acme" collected into vars["tenant"].
+// A variable matches exactly one label, i.e. it can never match a value containing a dot.
+//
+// Like hostnames in general, the pattern is matched case-insensitively.
+// Values for variables are collected in lowercase.
+// A port number and a trailing dot in the request hostname (as in "example.com.:8080") are ignored.
+//
+// Host() can be used to serve multiple virtual hosts from the same [Matcher] tree:
+//
+//	m := pr.Choice(
+//		pr.Host("www.example.com", websiteMatcher),
+//		pr.Host("{tenant}.api.example.com", apiMatcher),
+//	)
+func Host(pattern string, matcher Matcher) Matcher {
+	labels := strings.Split(pattern, ".")
+	for idx, label := range labels {
+		if label == "" || label == "{}" {
+			panic(fmt.Sprintf("Host() called with invalid pattern %q", pattern))
+		}
+		// variable names are kept as written, since they are used as keys in `vars`
+		if !isHostVariable(label) {
+			labels[idx] = strings.ToLower(label)
+		}
+	}
+
+	return condition(fmt.Sprintf("Host(%q)", pattern), matcher.downcast(), func(s *matchState) bool {
+		// NOTE: Variables are collected separately below because they shall only be collected if the request is accepted.
+		rest := normalizedHostname(s.request)
+		for idx, label := range labels {
+			value, remainder, found := strings.Cut(rest, ".")
+			if found == (idx == len(labels)-1) {
+				return false // hostname has too few or too many labels
+			}
+			if value == "" || (!isHostVariable(label) && value != label) {
+				return false
+			}
+			rest = remainder
+		}
+		return true
+	}, func(s *matchState) {
+		rest := normalizedHostname(s.request)
+		for _, label := range labels {
+			var value string
+			value, rest, _ = strings.Cut(rest, ".")
+			if isHostVariable(label) {
+				s.vars[label[1:len(label)-1]] = value
+			}
+		}
+	})
+}
+
+func isHostVariable(label string) bool {
+	return strings.HasPrefix(label, "{") && strings.HasSuffix(label, "}")
+}
+
+func normalizedHostname(r *http.Request) string {
+	hostname := r.Host
+	if h, _, err := net.SplitHostPort(hostname); err == nil {
+		hostname = h
+	}
+	return strings.ToLower(strings.TrimSuffix(hostname, "."))
+}
+
+// Header is a [Matcher] that accepts requests only if they have a header with the given name and value,
+// and if the next matcher accepts the request.
+// It does not consume any path elements.
+//
+// The value must match exactly. If the header appears multiple times, any of its values may match.
+// For example, this can be used to serve different versions of an API based on a version header:
+//
+//	m := pr.Choice(
+//		pr.Header("X-Api-Version", "2", apiV2),
+//		apiV1,
+//	)
+func Header(name, value string, matcher Matcher) Matcher {
+	key := textproto.CanonicalMIMEHeaderKey(name)
+	return condition(fmt.Sprintf("Header(%q, %q)", key, value), matcher.downcast(), func(s *matchState) bool {
+		return slices.Contains(s.request.Header[key], value)
+	}, nil)
+}
+
+// Query is a [Matcher] that accepts requests only if the URL query contains a parameter with the given name,
+// and if the next matcher accepts the request.
+// It does not consume any path elements.
+//
+// Any value is accepted for the query parameter, including the empty value (as in "?name" or "?name=").
+// The handler can obtain the value through [url.URL.Query] as usual.
+func Query(name string, matcher Matcher) Matcher {
+	return condition(fmt.Sprintf("Query(%q)", name), matcher.downcast(), func(s *matchState) bool {
+		return hasQueryParameter(s.request.URL.RawQuery, name)
+	}, nil)
+}
+
+// hasQueryParameter is equivalent to u.Query().Has(name), but avoids parsing the entire query into a map.
+func hasQueryParameter(rawQuery, name string) bool {
+	for rawQuery != "" {
+		var pair string
+		pair, rawQuery, _ = strings.Cut(rawQuery, "&")
+		key, _, _ := strings.Cut(pair, "=")
+		if key == name {
+			return true
+		}
+		if strings.ContainsAny(key, "%+") {
+			unescaped, err := url.QueryUnescape(key)
+			if err == nil && unescaped == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// condition builds a Matcher that only tries the next matcher if check() returns true.
+// If the request is accepted, collectVars() (if not nil) will be called to collect variables.
+func condition(description string, matcher realMatcher, check func(*matchState) bool, collectVars func(*matchState)) Matcher {
+	return realMatcher{
+		minLength: matcher.minLength,
+		maxLength: matcher.maxLength,
+		notFound:  matcher.notFound,
+		node:      node{kind: nodeCondition, value: description, children: []realMatcher{matcher}},
+		accept: func(path []string, s *matchState) HandlerFunc {
+			if !check(s) {
+				return nil
+			}
+			handlerFunc := matcher.accept(path, s)
+			if handlerFunc != nil && collectVars != nil {
+				collectVars(s)
+			}
+			return handlerFunc
+		},
+	}
+}
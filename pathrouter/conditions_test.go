@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	pr "go.xyrillian.de/gg/pathrouter"
+)
+
+func TestConditions(t *testing.T) {
+	h := func(msg string) pr.Matcher {
+		return pr.Handlers(pr.ByMethod{
+			http.MethodGet: func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+				fmt.Fprintf(w, "%s with tenant = %q, id = %q", msg, vars["tenant"], vars["id"])
+			},
+		})
+	}
+	m := pr.Choice(
+		pr.Host("www.example.com", pr.Here(h("website"))),
+		pr.Host("{tenant}.api.example.com", pr.Element("objects", pr.Variable("id", pr.Choice(
+			pr.Header("X-Api-Version", "2", pr.Query("dryrun", h("dry run of API v2"))),
+			pr.Header("X-Api-Version", "2", h("API v2")),
+			h("API v1"),
+		)))),
+	)
+	check := func(url string, headers map[string]string, expected string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, url, http.NoBody)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, req)
+		assert.Equal(t, fmt.Sprintf("%d: %s", rec.Code, strings.TrimSpace(rec.Body.String())), expected)
+	}
+	apiV2 := map[string]string{"X-API-Version": "2"}
+
+	// check Host()
+	check("http://www.example.com/", nil, `200: website with tenant = "", id = ""`)
+	check("http://WWW.Example.COM.:8080/", nil, `200: website with tenant = "", id = ""`)
+	check("http://example.com/", nil, "404: 404 page not found")
+	check("http://www.example.com.evil.com/", nil, "404: 404 page not found")
+	check("http://acme.api.example.com/objects/42", nil, `200: API v1 with tenant = "acme", id = "42"`)
+	check("http://ACME.api.example.com/objects/42", nil, `200: API v1 with tenant = "acme", id = "42"`)
+	check("http://acme.api.example.com/", nil, "404: 404 page not found")
+	check("http://api.example.com/objects/42", nil, "404: 404 page not found")
+	check("http://foo.acme.api.example.com/objects/42", nil, "404: 404 page not found")
+	check("http://.api.example.com/objects/42", nil, "404: 404 page not found")
+
+	// check Header() and Query()
+	check("http://acme.api.example.com/objects/42", apiV2, `200: API v2 with tenant = "acme", id = "42"`)
+	check("http://acme.api.example.com/objects/42?dryrun", apiV2, `200: dry run of API v2 with tenant = "acme", id = "42"`)
+	check("http://acme.api.example.com/objects/42?foo=bar&dryrun=true", apiV2, `200: dry run of API v2 with tenant = "acme", id = "42"`)
+	check("http://acme.api.example.com/objects/42?dry%72un=", apiV2, `200: dry run of API v2 with tenant = "acme", id = "42"`)
+	check("http://acme.api.example.com/objects/42?dryrunx=1", apiV2, `200: API v2 with tenant = "acme", id = "42"`)
+	check("http://acme.api.example.com/objects/42?dryrun", nil, `200: API v1 with tenant = "acme", id = "42"`)
+
+	// check introspection
+	assert.Equal(t, pr.Routes(m), []pr.Route{
+		{Pattern: "/", Methods: []string{"GET", "HEAD"}, Conditions: []string{`Host("www.example.com")`}},
+		{Pattern: "/objects/:id", Methods: []string{"GET", "HEAD"}, Conditions: []string{`Host("{tenant}.api.example.com")`, `Header("X-Api-Version", "2")`, `Query("dryrun")`}},
+		{Pattern: "/objects/:id", Methods: []string{"GET", "HEAD"}, Conditions: []string{`Host("{tenant}.api.example.com")`, `Header("X-Api-Version", "2")`}},
+		{Pattern: "/objects/:id", Methods: []string{"GET", "HEAD"}, Conditions: []string{`Host("{tenant}.api.example.com")`}},
+	})
+
+	// only the literal labels of the pattern are matched case-insensitively, variable names are used as written
+	mixedCase := pr.Host("{tenantID}.API.Example.com", pr.Here(pr.Handlers(pr.ByMethod{
+		http.MethodGet: func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+			fmt.Fprintf(w, "tenantID = %q", vars["tenantID"])
+		},
+	})))
+	rec := httptest.NewRecorder()
+	mixedCase.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://Acme.api.example.com/", http.NoBody))
+	assert.Equal(t, rec.Body.String(), `tenantID = "acme"`)
+
+	// URLFor() only builds the path, and does not require values for variables in Host()
+	hm := pr.Host("{tenant}.api.example.com", pr.Element("x", pr.Named("n", pr.Handlers(pr.ByMethod{http.MethodGet: noop}))))
+	path, err := pr.URLFor(hm, "n", nil)
+	assert.ErrEqual(t, err, nil)
+	assert.Equal(t, path, "/x")
+
+	// check panics
+	msg := assert.PanicsWith[string](t, func() { pr.Host("{}.example.com", pr.Handlers(nil)) })
+	assert.Equal(t, msg, `Host() called with invalid pattern "{}.example.com"`)
+	msg = assert.PanicsWith[string](t, func() { pr.Host("www..example.com", pr.Handlers(nil)) })
+	assert.Equal(t, msg, `Host() called with invalid pattern "www..example.com"`)
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter
+
+import (
+	"net/http"
+	"slices"
+)
+
+// Config contains optional customizations for the responses that package pathrouter generates by itself.
+// It is used with func [New].
+// Any fields that are left as nil will behave like when no Config is given.
+//
+// For example, JSON APIs may want to render errors as "application/problem+json" according to RFC 9457 instead of as plain text.
+type Config struct {
+	// Renders a response for requests whose path is not accepted by the Matcher.
+	// The default is [http.NotFound].
+	NotFound http.HandlerFunc
+	// Renders a response for requests whose path is accepted by [Handlers], but whose method does not have a handler there.
+	// The default renders a "405 Method Not Allowed" response with a plain-text body.
+	//
+	// The "Allow" header is set on the ResponseWriter before calling this function.
+	// The same list of methods is given in the allowedMethods argument, sorted alphabetically.
+	MethodNotAllowed func(w http.ResponseWriter, r *http.Request, allowedMethods []string)
+	// Like MethodNotAllowed, but only for requests with method OPTIONS.
+	// The default renders an empty "200 OK" response.
+	Options func(w http.ResponseWriter, r *http.Request, allowedMethods []string)
+}
+
+// New is a [Matcher] that behaves exactly like the given matcher,
+// but with some of its responses customized according to the given [Config].
+//
+// The customizations for responses generated by [Handlers] apply to all Handlers() within the given matcher.
+// When New() is nested within another New(), the innermost Config takes precedence.
+//
+// The customization of "404 Not Found" responses only takes effect when the matcher returned by New() is used directly
+// as a [http.Handler] (or with func [RedirectingHandler]), since a nested matcher cannot decide on behalf of its parent that the request shall not be accepted.
+// Wrapping the matcher returned by New() in matchers that do not consume path elements (e.g. [With], [WithRoute], [CORS], [Named] or [Host]) retains this customization.
+func New(matcher Matcher, cfg Config) Matcher {
+	inner := matcher.downcast()
+	return realMatcher{
+		minLength: inner.minLength,
+		maxLength: inner.maxLength,
+		notFound:  cfg.NotFound,
+		node:      node{kind: nodeConfig, children: []realMatcher{inner}},
+		accept: func(path []string, s *matchState) HandlerFunc {
+			previous := s.config
+			s.config = &cfg
+			handlerFunc := inner.accept(path, s)
+			s.config = previous
+			return handlerFunc
+		},
+	}
+}
+
+// rejectMethod returns the HandlerFunc that is used by Handlers() for methods that do not have a handler.
+func (c *Config) rejectMethod(e *endpoint) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		w.Header().Set("Allow", e.allowHeader)
+		if r.Method == http.MethodOptions {
+			if c != nil && c.Options != nil {
+				c.Options(w, r, slices.Clone(e.allowedMethods))
+			} else {
+				http.Error(w, "", http.StatusOK)
+			}
+		} else {
+			if c != nil && c.MethodNotAllowed != nil {
+				c.MethodNotAllowed(w, r, slices.Clone(e.allowedMethods))
+			} else {
+				http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	pr "go.xyrillian.de/gg/pathrouter"
+)
+
+func TestConfig(t *testing.T) {
+	problem := func(w http.ResponseWriter, status int, detail string) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"status":%d,"detail":%q}`, status, detail)
+	}
+	cfg := pr.Config{
+		NotFound: func(w http.ResponseWriter, r *http.Request) {
+			problem(w, http.StatusNotFound, "no such endpoint: "+r.URL.Path)
+		},
+		MethodNotAllowed: func(w http.ResponseWriter, r *http.Request, allowedMethods []string) {
+			problem(w, http.StatusMethodNotAllowed, "allowed methods are "+strings.Join(allowedMethods, " and "))
+		},
+		Options: func(w http.ResponseWriter, r *http.Request, allowedMethods []string) {
+			w.Header().Set("Cache-Control", "max-age=3600")
+			w.WriteHeader(http.StatusNoContent)
+		},
+	}
+
+	api := pr.Element("api", pr.Handlers(pr.ByMethod{
+		http.MethodPost: func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+			w.WriteHeader(http.StatusCreated)
+		},
+	}))
+	legacy := pr.Element("legacy", pr.Handlers(pr.ByMethod{
+		http.MethodPost: func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+			w.WriteHeader(http.StatusCreated)
+		},
+	}))
+	m := pr.New(pr.Choice(api, pr.New(legacy, pr.Config{})), cfg)
+
+	check := func(method, path, expected string) {
+		t.Helper()
+		req := httptest.NewRequest(method, "http://localhost"+path, http.NoBody)
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, req)
+		actual := fmt.Sprintf("%d %s %s: %s", rec.Code, rec.Header().Get("Allow"), rec.Header().Get("Content-Type"), strings.TrimSpace(rec.Body.String()))
+		assert.Equal(t, actual, expected)
+	}
+
+	check("POST", "/api", "201  : ")
+	check("GET", "/api", `405 POST application/problem+json: {"status":405,"detail":"allowed methods are POST"}`)
+	check("OPTIONS", "/api", "204 POST : ")
+	check("GET", "/unknown", `404  application/problem+json: {"status":404,"detail":"no such endpoint: /unknown"}`)
+
+	// the 404 customization is retained when wrapped in matchers that do not consume path elements
+	for _, wrapped := range []pr.Matcher{
+		pr.With(func(next pr.HandlerFunc) pr.HandlerFunc { return next }, m),
+		pr.WithRoute(func(_ pr.Route, next pr.HandlerFunc) pr.HandlerFunc { return next }, m),
+		pr.CORS(pr.CORSPolicy{AllowedOrigins: []string{"*"}}, m),
+		pr.Header("X-Foo", "bar", m),
+	} {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/unknown", http.NoBody)
+		req.Header.Set("X-Foo", "bar")
+		rec := httptest.NewRecorder()
+		wrapped.ServeHTTP(rec, req)
+		assert.Equal(t, rec.Code, http.StatusNotFound)
+		assert.Equal(t, rec.Header().Get("Content-Type"), "application/problem+json")
+	}
+
+	// inner Config takes precedence
+	check("GET", "/legacy", "405 POST text/plain; charset=utf-8: Method Not Allowed")
+	check("OPTIONS", "/legacy", "200 POST text/plain; charset=utf-8: ")
+
+	// New() is transparent to introspection
+	assert.Equal(t, pr.Routes(m), []pr.Route{
+		{Pattern: "/api", Methods: []string{"POST"}},
+		{Pattern: "/legacy", Methods: []string{"POST"}},
+	})
+}
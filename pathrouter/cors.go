@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy appears in func [CORS].
+// It describes which cross-origin requests are allowed by browsers, as defined in the [Fetch Standard].
+//
+// [Fetch Standard]: https://fetch.spec.whatwg.org/#http-cors-protocol
+type CORSPolicy struct {
+	// The origins (e.g. "https://example.com") from which cross-origin requests are allowed.
+	// The special value "*" allows requests from any origin, but cannot be combined with AllowCredentials.
+	AllowedOrigins []string
+	// The request headers that may be used in cross-origin requests (in addition to CORS-safelisted request headers).
+	AllowedHeaders []string
+	// The response headers that may be read by the requesting script (in addition to CORS-safelisted response headers).
+	ExposedHeaders []string
+	// Whether cross-origin requests may include credentials like cookies or HTTP Basic Auth.
+	AllowCredentials bool
+	// How long browsers may cache the result of a preflight request.
+	// If zero, the "Access-Control-Max-Age" header is omitted, and the browser default applies.
+	MaxAge time.Duration
+}
+
+// CORS is a [Matcher] that behaves like the given matcher,
+// but implements the CORS protocol for all requests accepted by the given matcher according to the given policy.
+//
+// Preflight requests (OPTIONS requests with an "Access-Control-Request-Method" header) are answered directly by CORS().
+// The "Access-Control-Allow-Methods" header is filled from the actual set of methods accepted by the respective [Handlers].
+// All other requests are passed on to their respective handlers, with additional "Access-Control-*" headers added to the response as appropriate.
+// This way, request handlers do not need to care about CORS at all:
+//
+//	m := pr.CORS(pr.CORSPolicy{
+//		AllowedOrigins: []string{"https://dashboard.example.com"},
+//		AllowedHeaders: []string{"Content-Type", "X-Auth-Token"},
+//		MaxAge:         time.Hour,
+//	}, apiMatcher)
+//
+// Within subtrees handled by [Mount], preflight requests are passed on to the mounted handler like all other requests,
+// since CORS() cannot know which methods the mounted handler accepts.
+//
+// Requests from origins that are not allowed by the policy are handled as if CORS() was not there,
+// except that responses carry "Vary: Origin" if the policy lists specific origins, to ensure correct behavior of shared caches.
+// In this case, browsers will refuse to make the response available to the requesting script.
+//
+// As required by the Fetch Standard, a policy allowing any origin cannot allow credentials.
+// CORS() panics if AllowedOrigins contains "*" while AllowCredentials is true.
+func CORS(policy CORSPolicy, matcher Matcher) Matcher {
+	inner := matcher.downcast()
+
+	// precompute header values
+	var (
+		allowAnyOrigin = slices.Contains(policy.AllowedOrigins, "*")
+		allowedOrigins = make(map[string]bool, len(policy.AllowedOrigins))
+		allowHeaders   = strings.Join(policy.AllowedHeaders, ", ")
+		exposeHeaders  = strings.Join(policy.ExposedHeaders, ", ")
+		maxAge         = ""
+	)
+	for _, origin := range policy.AllowedOrigins {
+		allowedOrigins[origin] = true
+	}
+	if allowAnyOrigin && policy.AllowCredentials {
+		// reflecting arbitrary origins would allow any website to make credentialed requests
+		panic(`CORS() called with AllowedOrigins = "*" and AllowCredentials = true`)
+	}
+	if policy.MaxAge > 0 {
+		maxAge = strconv.FormatInt(int64(policy.MaxAge/time.Second), 10)
+	}
+
+	// writes the headers that are common to preflight and normal responses
+	writeCommonHeaders := func(h http.Header, origin string) {
+		if allowAnyOrigin {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+			h.Add("Vary", "Origin")
+		}
+		if policy.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+	}
+
+	return realMatcher{
+		minLength: inner.minLength,
+		maxLength: inner.maxLength,
+		notFound:  inner.notFound,
+		node:      node{kind: nodeCORS, children: []realMatcher{inner}},
+		accept: func(path []string, s *matchState) HandlerFunc {
+			handlerFunc := inner.accept(path, s)
+			if handlerFunc == nil {
+				return nil
+			}
+			origin := s.request.Header.Get("Origin")
+			if origin == "" || !(allowAnyOrigin || allowedOrigins[origin]) {
+				if allowAnyOrigin {
+					return handlerFunc
+				}
+				// the response depends on the Origin header even if it does not contain any CORS headers
+				return func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+					w.Header().Add("Vary", "Origin")
+					handlerFunc(w, r, vars)
+				}
+			}
+
+			e := s.endpoint
+			if e != nil && s.request.Method == http.MethodOptions && s.request.Header.Get("Access-Control-Request-Method") != "" {
+				// answer preflight request
+				return func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+					h := w.Header()
+					writeCommonHeaders(h, origin)
+					h.Set("Access-Control-Allow-Methods", e.allowHeader)
+					if allowHeaders != "" {
+						h.Set("Access-Control-Allow-Headers", allowHeaders)
+					}
+					if maxAge != "" {
+						h.Set("Access-Control-Max-Age", maxAge)
+					}
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}
+
+			// add headers to normal response
+			return func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+				h := w.Header()
+				writeCommonHeaders(h, origin)
+				if exposeHeaders != "" {
+					h.Set("Access-Control-Expose-Headers", exposeHeaders)
+				}
+				handlerFunc(w, r, vars)
+			}
+		},
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.xyrillian.de/gg/assert"
+	pr "go.xyrillian.de/gg/pathrouter"
+)
+
+func TestCORS(t *testing.T) {
+	created := func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		w.WriteHeader(http.StatusCreated)
+	}
+	api := pr.Element("objects", pr.Handlers(pr.ByMethod{
+		http.MethodGet:  noop,
+		http.MethodPost: created,
+	}))
+
+	type checkFunc func(method, origin, requestMethod, expected string)
+	makeCheck := func(m pr.Matcher) checkFunc {
+		return func(method, origin, requestMethod, expected string) {
+			t.Helper()
+			req := httptest.NewRequest(method, "http://localhost/objects", http.NoBody)
+			if origin != "" {
+				req.Header.Set("Origin", origin)
+			}
+			if requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", requestMethod)
+			}
+			rec := httptest.NewRecorder()
+			m.ServeHTTP(rec, req)
+
+			var headers []string
+			for _, key := range []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Methods", "Access-Control-Allow-Headers", "Access-Control-Allow-Credentials", "Access-Control-Expose-Headers", "Access-Control-Max-Age", "Vary"} {
+				if value := rec.Header().Get(key); value != "" {
+					headers = append(headers, fmt.Sprintf("%s=%s", strings.TrimPrefix(key, "Access-Control-"), value))
+				}
+			}
+			actual := fmt.Sprintf("%d %s", rec.Code, strings.Join(headers, " "))
+			assert.Equal(t, strings.TrimSpace(actual), expected)
+		}
+	}
+
+	// policy with specific origins
+	check := makeCheck(pr.CORS(pr.CORSPolicy{
+		AllowedOrigins:   []string{"https://example.com"},
+		AllowedHeaders:   []string{"Content-Type", "X-Auth-Token"},
+		ExposedHeaders:   []string{"X-Request-Id"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}, api))
+
+	// requests without Origin or with an unknown Origin are not affected
+	// (besides Vary, since the response would be different for an allowed origin)
+	check("GET", "", "", "200 Vary=Origin")
+	check("OPTIONS", "", "", "200 Vary=Origin")
+	check("GET", "https://example.org", "", "200 Vary=Origin")
+	check("OPTIONS", "https://example.org", "POST", "200 Vary=Origin")
+
+	// preflight requests are answered with the methods that the respective endpoint accepts
+	check("OPTIONS", "https://example.com", "POST", "204 Allow-Origin=https://example.com Allow-Methods=GET, HEAD, POST Allow-Headers=Content-Type, X-Auth-Token Allow-Credentials=true Max-Age=600 Vary=Origin")
+	// OPTIONS requests without Access-Control-Request-Method are not preflights
+	check("OPTIONS", "https://example.com", "", "200 Allow-Origin=https://example.com Allow-Credentials=true Expose-Headers=X-Request-Id Vary=Origin")
+
+	// actual requests are passed on to the handler
+	check("GET", "https://example.com", "", "200 Allow-Origin=https://example.com Allow-Credentials=true Expose-Headers=X-Request-Id Vary=Origin")
+	check("POST", "https://example.com", "", "201 Allow-Origin=https://example.com Allow-Credentials=true Expose-Headers=X-Request-Id Vary=Origin")
+	check("DELETE", "https://example.com", "", "405 Allow-Origin=https://example.com Allow-Credentials=true Expose-Headers=X-Request-Id Vary=Origin")
+
+	// policy with wildcard origin
+	check = makeCheck(pr.CORS(pr.CORSPolicy{AllowedOrigins: []string{"*"}}, api))
+	check("OPTIONS", "https://example.org", "POST", "204 Allow-Origin=* Allow-Methods=GET, HEAD, POST")
+	check("GET", "https://example.org", "", "200 Allow-Origin=*")
+	check("GET", "", "", "200")
+
+	// wildcard origin cannot be combined with credentials
+	msg := assert.PanicsWith[string](t, func() {
+		pr.CORS(pr.CORSPolicy{AllowedOrigins: []string{"https://example.com", "*"}, AllowCredentials: true}, api)
+	})
+	assert.Equal(t, msg, `CORS() called with AllowedOrigins = "*" and AllowCredentials = true`)
+
+	// CORS() is transparent to introspection
+	assert.Equal(t, pr.Routes(pr.CORS(pr.CORSPolicy{}, api)), []pr.Route{
+		{Pattern: "/objects", Methods: []string{"GET", "HEAD", "POST"}},
+	})
+}
@@ -3,7 +3,11 @@
 
 package pathrouter
 
-import "go.xyrillian.de/gg/options"
+import (
+	"strings"
+
+	"go.xyrillian.de/gg/options"
+)
 
 // Element is a [Matcher] that accepts subpaths with at least one path element.
 // The first path element must be equal to the given value,
@@ -24,11 +28,26 @@ func element(value string, matcher realMatcher) Matcher {
 	return realMatcher{
 		minLength: matcher.minLength + 1,
 		maxLength: options.Map(matcher.maxLength, increment),
-		accept: func(path []string, vars map[string]string) HandlerFunc {
-			if len(path) == 0 || path[0] != value {
+		node:      node{kind: nodeElement, value: value, children: []realMatcher{matcher}},
+		accept: func(path []string, s *matchState) HandlerFunc {
+			if len(path) == 0 {
+				return nil
+			}
+			if path[0] == value {
+				return matcher.accept(path[1:], s)
+			}
+			if !s.foldCase || !strings.EqualFold(path[0], value) {
 				return nil
 			}
-			return matcher.accept(path[1:], vars)
+
+			// when matching case-insensitively, record the canonical spelling in `path` (see func RedirectingHandler)
+			original := path[0]
+			path[0] = value
+			handlerFunc := matcher.accept(path[1:], s)
+			if handlerFunc == nil {
+				path[0] = original
+			}
+			return handlerFunc
 		},
 	}
 }
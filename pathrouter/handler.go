@@ -5,6 +5,7 @@ package pathrouter
 
 import (
 	"fmt"
+	"maps"
 	"net/http"
 	"slices"
 	"strings"
@@ -39,6 +40,10 @@ type ByMethod map[string]HandlerFunc
 //		}))),
 //	))
 //
+// If the request method does not have a handler, Handlers will render a "405 Method Not Allowed" response,
+// or a "200 OK" response for the OPTIONS method, both with an "Allow" header listing all methods that have handlers.
+// The response bodies can be customized through [Config].
+//
 // If there is a handler for [http.MethodGet], but none for [http.MethodHead], the GET handler will be called for HEAD as well.
 // To have a GET handler, but no HEAD handler, set the handler for [http.MethodHead] to nil.
 // Any other use of a nil [HandlerFunc] is invalid and will cause a panic.
@@ -61,39 +66,39 @@ func Handlers(m ByMethod) Matcher {
 	}
 
 	// precomputations for accept()
-	allowHeader := m.buildAllowHeader()
-	serve := func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
-		handler, ok := m[r.Method]
-		if ok {
-			handler(w, r, vars)
-			return
-		}
-
-		w.Header().Set("Allow", allowHeader)
-		if r.Method == http.MethodOptions {
-			http.Error(w, "", http.StatusOK)
-		} else {
-			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
-		}
+	allowedMethods := m.allowedMethods()
+	e := &endpoint{
+		allowedMethods: allowedMethods,
+		allowHeader:    strings.Join(allowedMethods, ", "),
 	}
 
 	return realMatcher{
 		minLength: 0,
 		maxLength: Some(0),
-		accept: func(path []string, vars map[string]string) HandlerFunc {
+		node:      node{kind: nodeHandlers, methods: allowedMethods},
+		accept: func(path []string, s *matchState) HandlerFunc {
 			if len(path) != 0 {
 				return nil
 			}
-			return serve
+			s.endpoint = e
+			handler, ok := m[s.request.Method]
+			if ok {
+				return handler
+			}
+			return s.config.rejectMethod(e)
 		},
 	}
 }
 
-func (m ByMethod) buildAllowHeader() string {
-	allowedMethods := make([]string, 0, len(m))
-	for method := range m {
-		allowedMethods = append(allowedMethods, method)
-	}
-	slices.Sort(allowedMethods)
-	return strings.Join(allowedMethods, ", ")
+// endpoint holds precomputed information about a Handlers() matcher.
+// It appears in type matchState.
+type endpoint struct {
+	// The sorted list of methods that have handlers.
+	allowedMethods []string
+	// The value for the "Allow" header in responses generated by Handlers().
+	allowHeader string
+}
+
+func (m ByMethod) allowedMethods() []string {
+	return slices.Sorted(maps.Keys(m))
 }
@@ -43,9 +43,10 @@ func here(matcher realMatcher) Matcher {
 	return realMatcher{
 		minLength: 0,
 		maxLength: Some(1),
-		accept: func(path []string, vars map[string]string) HandlerFunc {
+		node:      node{kind: nodeHere, children: []realMatcher{matcher}},
+		accept: func(path []string, s *matchState) HandlerFunc {
 			if len(path) == 0 || (len(path) == 1 && path[0] == "") {
-				return matcher.accept(nil, vars)
+				return matcher.accept(nil, s)
 			}
 			return nil
 		},
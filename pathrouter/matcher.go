@@ -41,9 +41,12 @@
 // thus making it extremely fast at the expense of reducing flexibility in what can be matched.
 // For instance, in the example above, requests for /v1/objects/:id will accept any non-empty string for the "id" variable.
 // Package pathrouter expects that request handlers will perform additional format checks on extracted path variables as required.
+// For the most common formats, typed variants like [IntVariable] and [UUIDVariable] are provided.
+// Arbitrary format checks can be expressed with [VariableMatching], as long as they do not need to look beyond a single path element.
 //
 // Unlike other fast HTTP router libraries such as [httprouter] or [httptreemux], pathrouter can match a catch-all path (i.e. a variable extending over multiple path elements) anywhere in the path, not just at the end.
-// The only limitation with catch-all paths is that only one catch-all path may be matched per route, e.g. "/v1/objects/*path/relations" can be matched, but "v1/objects/*path/compare/*otherpath" cannot.
+// Multiple catch-all paths may appear in the same route, e.g. "/v1/objects/*path/compare/*otherpath".
+// See [CatchAllVariable] for how ambiguous matches are resolved.
 //
 // # Handling of escape sequences in paths
 //
@@ -66,6 +69,7 @@ package pathrouter
 
 import (
 	"context"
+	"encoding/binary"
 	"net/http"
 	"net/url"
 	"strings"
@@ -84,6 +88,8 @@ var (
 //
 // Matcher implements the ServeHTTP method of [http.Handler] and can thus be used with any net/http facility like [http.ListenAndServe].
 //
+// The "404 Not Found" response can be customized by wrapping the Matcher with func [New].
+//
 // Alternatively, the TryServeHTTP method behaves like ServeHTTP, but will not render a "404 Not Found" response
 // when the matcher is not capable of handling requests with the given path, instead only returning false without touching the ResponseWriter.
 // This method may be useful when composing e.g. multiple [Matcher] instances that each implement a different API with different endpoints.
@@ -97,30 +103,127 @@ type Matcher interface {
 }
 
 type realMatcher struct {
-	accept func(path []string, vars map[string]string) HandlerFunc
+	accept acceptFunc
 
 	// The smallest len(path) that accept() can accept.
 	minLength int
 	// The largest len(path) that accept() can accept, or None if accept() can handle arbitrarily long paths.
 	maxLength Option[int]
+
+	// If set by New(), renders the response for requests that are not accepted.
+	// Matchers that wrap another matcher without consuming path elements (e.g. With() or CORS()) retain this value.
+	notFound http.HandlerFunc
+
+	// Describes the structure of this matcher for introspection purposes (e.g. in func Routes).
+	// Routing decisions are made only by accept(); this field is not consulted while serving requests.
+	node node
+}
+
+// acceptFunc appears in type realMatcher.
+// It inspects the given subpath and returns the HandlerFunc that shall handle the request, or nil if the request is declined.
+type acceptFunc = func(path []string, s *matchState) HandlerFunc
+
+// matchState holds the state of a single request while it is being routed through the accept() functions of a matcher tree.
+type matchState struct {
+	// The request being routed.
+	request *http.Request
+	// The full request path (as returned by extractPath), of which each accept() receives a suffix.
+	path []string
+	// The variables extracted from the request so far.
+	vars map[string]string
+	// The configuration that was set by the closest ancestor New() matcher, or nil if there is none.
+	config *Config
+	// Set by the Handlers() matcher that accepted the request. Only valid if accept() returned a HandlerFunc.
+	endpoint *endpoint
+	// Subpaths that were declined by the inner matcher of a CatchAllVariable().
+	// This is only filled for CatchAllVariable() instances containing another CatchAllVariable(), and is lazily allocated.
+	declinedSubpaths map[subpathAttempt]bool
+	// If true, Element() shall compare path elements case-insensitively.
+	// When an element matches in this way, it will be overwritten with the spelling that was given to Element().
+	foldCase bool
+	// If true, Choice() shall record which of its options accepted the request in choiceTrace (see func WithRoute).
+	traceChoices bool
+	// The indexes of the options that accepted the request in each Choice(), encoded as uvarints.
+	// Since indexes are recorded when accept() returns, they are ordered from the innermost to the outermost Choice().
+	choiceTrace []byte
+}
+
+// traceChoice records that the option with the given index accepted the request within a Choice().
+// The index refers to the list of options after flattening nested Choice() matchers (see func flattenChoices).
+func (s *matchState) traceChoice(idx int) {
+	if s.traceChoices {
+		s.choiceTrace = binary.AppendUvarint(s.choiceTrace, uint64(idx))
+	}
+}
+
+// subpathAttempt appears in type matchState.
+type subpathAttempt struct {
+	// Identifies the CatchAllVariable() instance.
+	catchAllID uint64
+	// The length of the subpath that was given to the inner matcher.
+	// Since each subpath is a suffix of matchState.path, its length identifies it uniquely.
+	length int
+}
+
+// node appears in type realMatcher.
+type node struct {
+	kind nodeKind
+	// For nodeElement, the literal value (with "/" stored as "");
+	// for nodeVariable and nodeCatchAllVariable, the variable name;
+	// for nodeNamed, the route name;
+	// for nodeCondition, a description of the condition (e.g. `Header("X-Api-Version", "2")`).
+	value string
+	// For nodeHandlers, the sorted list of accepted methods.
+	methods []string
+	// For nodeVariable, an optional check on the variable value (as given to VariableMatching).
+	predicate func(string) bool
+	// The matchers contained within this matcher (none for nodeHandlers, multiple for nodeChoice, one otherwise).
+	children []realMatcher
 }
 
+// nodeKind is an enum that appears in type node.
+type nodeKind uint
+
+const (
+	nodeHandlers nodeKind = iota
+	nodeElement
+	nodeVariable
+	nodeCatchAllVariable
+	nodeChoice
+	nodeHere
+	nodeNamed
+	nodeWith
+	nodeCondition
+	nodeConfig
+	nodeCORS
+	nodeMount
+	nodeWithRoute
+)
+
 // ServeHTTP implements the [Matcher] interface.
 func (m realMatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if !m.TryServeHTTP(w, r) {
+		m.renderNotFound(w, r)
+	}
+}
+
+func (m realMatcher) renderNotFound(w http.ResponseWriter, r *http.Request) {
+	if m.notFound == nil {
 		http.NotFound(w, r)
+	} else {
+		m.notFound(w, r)
 	}
 }
 
 // TryServeHTTP implements the [Matcher] interface.
 func (m realMatcher) TryServeHTTP(w http.ResponseWriter, r *http.Request) bool {
 	path := extractPath(r.URL)
-	vars := make(map[string]string)
-	handlerFunc := m.accept(path, vars)
+	s := matchState{request: r, path: path, vars: make(map[string]string)}
+	handlerFunc := m.accept(path, &s)
 	if handlerFunc == nil {
 		return false
 	} else {
-		handlerFunc(w, r, vars)
+		handlerFunc(w, r, s.vars)
 		return true
 	}
 }
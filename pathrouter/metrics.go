@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.xyrillian.de/gg/microprom"
+)
+
+// RequestMetrics counts requests and their durations, labeled by request method, route pattern and response status code.
+// It provides a middleware for use with func [WithRoute], and reports its metrics through package [microprom]:
+//
+//	var rm pr.RequestMetrics
+//	apiHandler := pr.WithRoute(rm.Middleware, apiMatcher)
+//	metricsHandler := microprom.Handler{
+//		Families: rm.Families(),
+//		Collect:  rm.Collect,
+//	}
+//
+// The following metric families are reported, each with labels "method", "route" and "code":
+//
+//   - "http_requests" (counter): the number of requests that were served.
+//   - "http_request_duration_seconds" (counter): the total time spent in serving those requests.
+//
+// Request methods that are not defined in net/http (e.g. [http.MethodGet]) are reported as "OTHER" to bound label cardinality.
+// If a handler does not write a status code explicitly, the status code 200 is assumed.
+//
+// The zero value is ready to use. A RequestMetrics instance must not be copied after first use.
+type RequestMetrics struct {
+	mutex sync.Mutex
+	stats map[requestMetricsKey]requestStats
+}
+
+// requestMetricsKey appears in type RequestMetrics.
+type requestMetricsKey struct {
+	method string
+	route  string
+	code   int
+}
+
+// requestStats appears in type RequestMetrics.
+type requestStats struct {
+	count           uint64
+	durationSeconds float64
+}
+
+var (
+	requestMetricsLabelNames = microprom.NewLabelNames("method", "route", "code")
+	knownMethods             = map[string]bool{
+		http.MethodConnect: true,
+		http.MethodDelete:  true,
+		http.MethodGet:     true,
+		http.MethodHead:    true,
+		http.MethodOptions: true,
+		http.MethodPatch:   true,
+		http.MethodPost:    true,
+		http.MethodPut:     true,
+		http.MethodTrace:   true,
+	}
+)
+
+// Middleware records metrics for each request served by the next handler.
+// It has the signature expected by func [WithRoute].
+func (rm *RequestMetrics) Middleware(route Route, next HandlerFunc) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		start := time.Now()
+		sw := &statusRecordingWriter{ResponseWriter: w}
+		next(sw, r, vars)
+		duration := time.Since(start)
+
+		method := r.Method
+		if !knownMethods[method] {
+			method = "OTHER"
+		}
+		key := requestMetricsKey{method, route.Pattern, sw.status}
+		if key.code == 0 {
+			key.code = http.StatusOK
+		}
+
+		rm.mutex.Lock()
+		defer rm.mutex.Unlock()
+		if rm.stats == nil {
+			rm.stats = make(map[requestMetricsKey]requestStats)
+		}
+		stats := rm.stats[key]
+		stats.count++
+		stats.durationSeconds += duration.Seconds()
+		rm.stats[key] = stats
+	}
+}
+
+// Families returns the metric families reported by this RequestMetrics instance,
+// for use in [microprom.Handler].
+func (rm *RequestMetrics) Families() map[microprom.MetricFamilyName]microprom.MetricFamilyInfo {
+	return map[microprom.MetricFamilyName]microprom.MetricFamilyInfo{
+		"http_requests": {
+			Type: microprom.MetricTypeCounter,
+			Help: "Number of HTTP requests served, by method, route and status code.",
+		},
+		"http_request_duration_seconds": {
+			Type: microprom.MetricTypeCounter,
+			Help: "Total time spent serving HTTP requests, by method, route and status code.",
+		},
+	}
+}
+
+// Collect reports the metrics recorded so far. It has the signature expected by [microprom.Handler].
+func (rm *RequestMetrics) Collect(ctx context.Context, ms *microprom.MetricSet) error {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+	for key, stats := range rm.stats {
+		labels := ms.FormatLabels(requestMetricsLabelNames, key.method, key.route, strconv.Itoa(key.code))
+		ms.Add("http_requests", labels, float64(stats.count))
+		ms.Add("http_request_duration_seconds", labels, stats.durationSeconds)
+	}
+	return nil
+}
+
+// statusRecordingWriter is a ResponseWriter that remembers the status code of the response.
+type statusRecordingWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader implements the [http.ResponseWriter] interface.
+func (w *statusRecordingWriter) WriteHeader(status int) {
+	// informational responses (1xx) may be followed by the actual response
+	if w.status == 0 && status >= 200 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements the [http.ResponseWriter] interface.
+func (w *statusRecordingWriter) Write(buf []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(buf)
+}
+
+// Unwrap allows [http.ResponseController] to access the original ResponseWriter.
+func (w *statusRecordingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	. "go.xyrillian.de/gg/option"
+)
+
+// Mount is a [Matcher] that accepts all subpaths, including the empty subpath, and all request methods.
+// Requests are served by the given [http.Handler] as if the subpath were the entire request path.
+// This is useful for embedding third-party handlers (e.g. net/http/pprof, a file server or another router) into a matcher tree:
+//
+//	m := pr.Choice(
+//		pr.Element("v1", apiMatcher),
+//		pr.Element("static", pr.Mount(assetHandler)),
+//	)
+//
+// In this example, a request for "/static/css/main.css" will be served by assetHandler with URL.Path = "/css/main.css".
+// The rewritten path always starts with a slash, so requests for "/static" and "/static/" will both be served with URL.Path = "/".
+// The original request is not modified; the handler receives a shallow copy with a rewritten URL.
+//
+// The stripped prefix (in this example, "/static") can be obtained by the handler through func [MountPrefix],
+// e.g. in order to generate links to other files within the same subtree.
+//
+// Since the given handler takes responsibility for all subpaths and request methods,
+// Mount() will never decline a request, and the next options in an enclosing [Choice] will not be tried.
+// Within a matcher tree, Mount() is usually the last option in its Choice().
+func Mount(h http.Handler) Matcher {
+	if h == nil {
+		panic("Mount() called with h = nil")
+	}
+
+	return realMatcher{
+		minLength: 0,
+		maxLength: None[int](),
+		node:      node{kind: nodeMount},
+		accept: func(path []string, s *matchState) HandlerFunc {
+			prefix := strings.TrimSuffix("/"+strings.Join(s.path[:len(s.path)-len(path)], "/"), "/")
+			remainder := "/" + strings.Join(path, "/")
+			return func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+				h.ServeHTTP(w, stripPrefix(r, prefix, remainder))
+			}
+		},
+	}
+}
+
+// mountPrefixKey is the context key for the value returned by MountPrefix().
+type mountPrefixKey struct{}
+
+// MountPrefix returns the part of the request path that was stripped by [Mount], without trailing slash.
+// It is given in escaped form, so it can be used as a prefix to build URLs.
+//
+// If requests pass through multiple Mount() layers (e.g. because the handler given to Mount() is another [Matcher] using Mount() itself),
+// the prefixes are concatenated, so the result is always a prefix of the original request path.
+// If the request did not pass through Mount(), the empty string is returned.
+func MountPrefix(r *http.Request) string {
+	prefix, _ := r.Context().Value(mountPrefixKey{}).(string)
+	return prefix
+}
+
+// stripPrefix builds the request that Mount() passes on to its handler.
+func stripPrefix(r *http.Request, prefix, remainder string) *http.Request {
+	ctx := context.WithValue(r.Context(), mountPrefixKey{}, MountPrefix(r)+prefix)
+	r2 := r.WithContext(ctx)
+	u := *r.URL
+	u.Path = pathUnescape(remainder)
+	u.RawPath = remainder
+	r2.URL = &u
+	return r2
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	pr "go.xyrillian.de/gg/pathrouter"
+)
+
+func TestMount(t *testing.T) {
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s path=%q rawPath=%q prefix=%q", r.Method, r.URL.Path, r.URL.RawPath, pr.MountPrefix(r))
+	})
+	inner := pr.Choice(
+		pr.Element("info", pr.Handlers(pr.ByMethod{
+			http.MethodGet: func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+				fmt.Fprintf(w, "info prefix=%q", pr.MountPrefix(r))
+			},
+		})),
+		pr.Element("nested", pr.Mount(echo)),
+	)
+	m := pr.Choice(
+		pr.Element("v1", pr.Element("objects", pr.Handlers(pr.ByMethod{http.MethodGet: noop}))),
+		pr.Element("static", pr.Mount(echo)),
+		pr.Element("sub", pr.Variable("id", pr.Mount(inner))),
+	)
+
+	check := func(method, path, expected string) {
+		t.Helper()
+		req := httptest.NewRequest(method, "http://localhost"+path, http.NoBody)
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, req)
+		actual := strings.TrimSpace(fmt.Sprintf("%d %s", rec.Code, rec.Body.String()))
+		assert.Equal(t, actual, expected)
+	}
+
+	// Mount() accepts all subpaths and methods
+	check("GET", "/static/css/main.css", `200 GET path="/css/main.css" rawPath="/css/main.css" prefix="/static"`)
+	check("DELETE", "/static/css/", `200 DELETE path="/css/" rawPath="/css/" prefix="/static"`)
+	check("GET", "/static/", `200 GET path="/" rawPath="/" prefix="/static"`)
+	check("GET", "/static", `200 GET path="/" rawPath="/" prefix="/static"`)
+
+	// escape sequences are retained in RawPath
+	check("GET", "/static/a%2Fb/c", `200 GET path="/a/b/c" rawPath="/a%2Fb/c" prefix="/static"`)
+
+	// prefixes are concatenated when going through multiple Mount() layers
+	check("GET", "/sub/42/info", `200 info prefix="/sub/42"`)
+	check("GET", "/sub/42/nested/foo", `200 GET path="/foo" rawPath="/foo" prefix="/sub/42/nested"`)
+	check("GET", "/sub/42/other", "404 404 page not found")
+
+	// other routes are not affected
+	check("GET", "/v1/objects", "200")
+	check("GET", "/v1/foo", "404 404 page not found")
+
+	assert.Equal(t, pr.Routes(m), []pr.Route{
+		{Pattern: "/v1/objects", Methods: []string{"GET", "HEAD"}},
+		{Pattern: "/static/*"},
+		{Pattern: "/sub/:id/*"},
+	})
+}
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Named is a [Matcher] that behaves exactly like the given matcher,
+// but attaches a name to the route leading up to it.
+// This name can be used to build request paths for this route with func [URLFor]:
+//
+//	m := pr.Element("v1", pr.Element("objects", pr.Choice(
+//		pr.Element("new", pr.Handlers(pr.ByMethod{
+//			http.MethodPost: api.CreateObject,
+//		})),
+//		pr.Variable("id", pr.Named("get-object", pr.Handlers(pr.ByMethod{
+//			http.MethodGet: api.GetObject,
+//		}))),
+//	)))
+//
+//	// in api.CreateObject
+//	location, err := pr.URLFor(m, "get-object", map[string]string{"id": newObject.ID})
+//
+// The next matcher must match exactly one route, so currently only [Handlers] and [Here] are allowed.
+func Named(name string, matcher Matcher) Matcher {
+	return named(name, matcher.downcast())
+}
+
+func named(name string, matcher realMatcher) Matcher {
+	if name == "" {
+		panic(`Named() called with name = ""`)
+	}
+	if matcher.node.kind != nodeHandlers && matcher.node.kind != nodeHere {
+		panic("matcher within Named() must be Handlers() or Here()")
+	}
+
+	return realMatcher{
+		minLength: matcher.minLength,
+		maxLength: matcher.maxLength,
+		notFound:  matcher.notFound,
+		node:      node{kind: nodeNamed, value: name, children: []realMatcher{matcher}},
+		accept:    matcher.accept,
+	}
+}
+
+// URLFor builds a request path for the route with the given name (as declared by func [Named]) within the given [Matcher] tree.
+// If multiple routes have the same name, the first one (in the order reported by func [Routes]) is used.
+//
+// Only the path is built. Conditions on other parts of the request (as declared by e.g. [Host] or [Header]) are not reflected in the result,
+// so variables from a Host() pattern are not required in vars, and are ignored if given.
+// If a route has such conditions, the caller is responsible for sending the request in a way that satisfies them.
+//
+// Each variable in the route is filled with the respective value from vars, escaped such that the matcher will extract the exact same value:
+// In a [Variable], slashes will be escaped as "%2F", whereas in a [CatchAllVariable], slashes will be retained as path separators.
+// Routes declared with [Here] are rendered without trailing slash.
+// In routes with multiple [CatchAllVariable], the matcher may split the resulting path differently than given in vars
+// if a value for a catch-all variable other than the first one contains the literal path elements that separate them.
+//
+// An error is returned if there is no route with this name,
+// or if vars is missing a value for any of the variables in this route,
+// or if any of those values cannot be matched by the route (e.g. because it is empty),
+// or if any path element would be "." or "..", since HTTP clients would resolve these before sending the request.
+func URLFor(m Matcher, name string, vars map[string]string) (string, error) {
+	var (
+		found   bool
+		matched route
+	)
+	walkRoutes(m.downcast(), route{}, func(r route) {
+		if !found && r.name == name {
+			found = true
+			matched = r
+		}
+	})
+	if !found {
+		return "", fmt.Errorf("no route named %q", name)
+	}
+
+	var err error
+	result := matched.render(func(s segment) string {
+		value, exists := vars[s.value]
+		switch {
+		case err != nil:
+			return ""
+		case !exists:
+			err = fmt.Errorf("cannot build URL for route %q: missing value for variable %q", name, s.value)
+			return ""
+		case s.kind == nodeVariable:
+			if value == "" {
+				err = fmt.Errorf("cannot build URL for route %q: empty value for variable %q", name, s.value)
+			} else if isDotSegment(value) {
+				err = fmt.Errorf("cannot build URL for route %q: value for variable %q may not be %q", name, s.value, value)
+			} else if s.predicate != nil && !s.predicate(value) {
+				err = fmt.Errorf("cannot build URL for route %q: value for variable %q is not acceptable: %q", name, s.value, value)
+			}
+			return url.PathEscape(value)
+		default:
+			parts := strings.Split(value, "/")
+			for idx, part := range parts {
+				if part == "" {
+					// empty parts would be normalized away by extractPath()
+					err = fmt.Errorf("cannot build URL for route %q: value for variable %q may not contain empty path elements, but got %q", name, s.value, value)
+					return ""
+				}
+				if isDotSegment(part) {
+					err = fmt.Errorf("cannot build URL for route %q: value for variable %q may not contain %q as a path element, but got %q", name, s.value, part, value)
+					return ""
+				}
+				parts[idx] = url.PathEscape(part)
+			}
+			return strings.Join(parts, "/")
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// isDotSegment returns whether the given path element would be resolved away by HTTP clients.
+// Escaping as "%2E" does not help since RFC 3986 section 6.2.2.2 allows clients to normalize it into ".".
+func isDotSegment(value string) bool {
+	return value == "." || value == ".."
+}
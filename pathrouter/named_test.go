@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	pr "go.xyrillian.de/gg/pathrouter"
+)
+
+func TestURLFor(t *testing.T) {
+	var lastVars map[string]string
+	record := func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		lastVars = vars
+	}
+	m := pr.Element("v2", pr.Choice(
+		pr.Element("/", pr.Named("toplevel", pr.Handlers(pr.ByMethod{
+			http.MethodGet: record,
+		}))),
+		pr.CatchAllVariable("repo", pr.Element("manifests", pr.Variable("reference", pr.Named("manifest", pr.Here(pr.Handlers(pr.ByMethod{
+			http.MethodGet: record,
+		})))))),
+	))
+
+	assert.Equal(t, pr.Routes(m), []pr.Route{
+		{Pattern: "/v2/", Methods: []string{"GET", "HEAD"}, Name: "toplevel"},
+		{Pattern: "/v2/*repo/manifests/:reference", Methods: []string{"GET", "HEAD"}, Name: "manifest"},
+	})
+
+	// happy path: check that built URLs roundtrip through the matcher
+	check := func(name string, vars map[string]string, expected string) {
+		t.Helper()
+		actual, err := pr.URLFor(m, name, vars)
+		assert.ErrEqual(t, err, nil)
+		assert.Equal(t, actual, expected)
+
+		lastVars = nil
+		req := httptest.NewRequest(http.MethodGet, "http://localhost"+actual, http.NoBody)
+		if !m.TryServeHTTP(httptest.NewRecorder(), req) {
+			t.Errorf("built URL %q was not matched", actual)
+		}
+		if len(vars) > 0 {
+			assert.Equal(t, lastVars, vars)
+		}
+	}
+	check("toplevel", nil, "/v2/")
+	check("manifest", map[string]string{"repo": "library/alpine", "reference": "latest"}, "/v2/library/alpine/manifests/latest")
+	check("manifest", map[string]string{"repo": "foo bar/100%", "reference": "a/b?c"}, "/v2/foo%20bar/100%25/manifests/a%2Fb%3Fc")
+
+	// error cases
+	_, err := pr.URLFor(m, "unknown", nil)
+	assert.ErrEqual(t, err, `no route named "unknown"`)
+	_, err = pr.URLFor(m, "manifest", map[string]string{"repo": "library/alpine"})
+	assert.ErrEqual(t, err, `cannot build URL for route "manifest": missing value for variable "reference"`)
+	_, err = pr.URLFor(m, "manifest", map[string]string{"repo": "library/alpine", "reference": ""})
+	assert.ErrEqual(t, err, `cannot build URL for route "manifest": empty value for variable "reference"`)
+	_, err = pr.URLFor(m, "manifest", map[string]string{"repo": "library//alpine", "reference": "latest"})
+	assert.ErrEqual(t, err, `cannot build URL for route "manifest": value for variable "repo" may not contain empty path elements, but got "library//alpine"`)
+	_, err = pr.URLFor(m, "manifest", map[string]string{"repo": "library/alpine", "reference": ".."})
+	assert.ErrEqual(t, err, `cannot build URL for route "manifest": value for variable "reference" may not be ".."`)
+	_, err = pr.URLFor(m, "manifest", map[string]string{"repo": "library/./alpine", "reference": "latest"})
+	assert.ErrEqual(t, err, `cannot build URL for route "manifest": value for variable "repo" may not contain "." as a path element, but got "library/./alpine"`)
+
+	// check panics during construction
+	msg := assert.PanicsWith[string](t, func() { pr.Named("", pr.Handlers(nil)) })
+	assert.Equal(t, msg, `Named() called with name = ""`)
+	msg = assert.PanicsWith[string](t, func() { pr.Named("foo", pr.Element("bar", pr.Handlers(nil))) })
+	assert.Equal(t, msg, `matcher within Named() must be Handlers() or Here()`)
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// The set of methods for which an OpenAPI 3.1 Path Item Object has a dedicated field.
+var openAPIMethods = []string{
+	http.MethodDelete,
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodPatch,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodTrace,
+}
+
+type openAPIDocument struct {
+	OpenAPI string                    `json:"openapi"`
+	Info    openAPIInfo               `json:"info"`
+	Paths   map[string]map[string]any `json:"paths"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Schema   map[string]any `json:"schema"`
+}
+
+// WriteOpenAPI writes a skeleton [OpenAPI 3.1] document describing the given [Matcher] tree into w.
+// The document is intended as a starting point for API documentation and contains only what can be inferred from the matcher tree:
+// the "paths" object with one Path Item Object for each route (as reported by func [Routes]),
+// the path parameters for each route, and an empty Operation Object for each accepted method.
+// The "info" object is filled with empty placeholder values.
+//
+// Since OpenAPI does not have a notion of catch-all variables,
+// a [CatchAllVariable] is rendered like a [Variable], e.g. "/v2/{repository}/manifests/{reference}".
+// Methods not supported by OpenAPI (e.g. WebDAV methods like "PROPFIND") are skipped.
+// Subtrees handled by [Mount] are skipped as well, since their endpoints cannot be inferred.
+//
+// [OpenAPI 3.1]: https://spec.openapis.org/oas/v3.1.1.html
+func WriteOpenAPI(w io.Writer, m Matcher) error {
+	doc := openAPIDocument{
+		OpenAPI: "3.1.1",
+		Paths:   make(map[string]map[string]any),
+	}
+
+	walkRoutes(m.downcast(), route{}, func(r route) {
+		if r.isMount() {
+			return
+		}
+		path := r.render(func(s segment) string { return "{" + s.value + "}" })
+		pathItem, exists := doc.Paths[path]
+		if !exists {
+			pathItem = make(map[string]any)
+			var params []openAPIParameter
+			for _, s := range r.segments {
+				if s.kind == nodeVariable || s.kind == nodeCatchAllVariable {
+					params = append(params, openAPIParameter{
+						Name:     s.value,
+						In:       "path",
+						Required: true,
+						Schema:   map[string]any{"type": "string"},
+					})
+				}
+			}
+			if len(params) > 0 {
+				pathItem["parameters"] = params
+			}
+			doc.Paths[path] = pathItem
+		}
+
+		for _, method := range r.methods {
+			if slices.Contains(openAPIMethods, method) {
+				pathItem[strings.ToLower(method)] = struct{}{}
+			}
+		}
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
@@ -140,6 +140,25 @@ func TestRouting(t *testing.T) {
 	check("GET", "/long/path/but/no/match", "404: 404 page not found", nil)
 	check("GET", "/shortpathbutnomatch", "404: 404 page not found", nil)
 
+	// check multiple CatchAllVariable() in the same route
+	m = pr.Element("diff", pr.CatchAllVariable("left", pr.Element("with", pr.CatchAllVariable("right", pr.Choice(
+		pr.Element("summary", pr.Handlers(pr.ByMethod{
+			http.MethodGet: h(http.StatusOK, "summary of $left vs. $right"),
+		})),
+		pr.Here(pr.Handlers(pr.ByMethod{
+			http.MethodGet: h(http.StatusOK, "diff of $left vs. $right"),
+		})),
+	)))))
+
+	check("GET", "/diff/a/with/b", "200: diff of a vs. b", nil)
+	check("GET", "/diff/a/with/b/", "200: diff of a vs. b/", nil) // the trailing slash is caught as part of $right
+	check("GET", "/diff/a/b/with/c/d", "200: diff of a/b vs. c/d", nil)
+	check("GET", "/diff/a/b/with/c/d/summary", "200: diff of a/b vs. c/d/summary", nil) // $right collects as much as possible
+	check("GET", "/diff/a/with/b/with/c", "200: diff of a/with/b vs. c", nil)
+	check("GET", "/diff/with/with/with", "200: diff of with vs. with", nil)
+	check("GET", "/diff/a/with", "404: 404 page not found", nil)
+	check("GET", "/diff/with/b", "404: 404 page not found", nil)
+
 	// check Variable()
 	m = pr.Element("nice", pr.Element("objects", pr.Variable("id", pr.Here(pr.Handlers(pr.ByMethod{
 		http.MethodPut: h(http.StatusCreated, "created object $id"),
@@ -153,6 +172,23 @@ func TestRouting(t *testing.T) {
 	check("PUT", "/nice/objects/4%2F2/", "201: created object 4/2", nil)
 }
 
+func TestManyCatchAllVariables(t *testing.T) {
+	// This route has so many catch-all variables that trying all possible splits of the path
+	// would take practically forever. This test can only complete if CatchAllVariable() avoids repeating work.
+	var m pr.Matcher = pr.Handlers(pr.ByMethod{http.MethodGet: noop})
+	for idx := range 6 {
+		m = pr.CatchAllVariable(fmt.Sprintf("var%d", idx), pr.Element("sep", m))
+	}
+
+	path := strings.Repeat("/sep", 200) + "/nomatch"
+	req := httptest.NewRequest(http.MethodGet, "http://localhost"+path, http.NoBody)
+	assert.Equal(t, m.TryServeHTTP(httptest.NewRecorder(), req), false)
+
+	path = strings.Repeat("/sep", 200)
+	req = httptest.NewRequest(http.MethodGet, "http://localhost"+path, http.NoBody)
+	assert.Equal(t, m.TryServeHTTP(httptest.NewRecorder(), req), true)
+}
+
 func TestPanics(t *testing.T) {
 	check := func(expected string, action func()) {
 		t.Helper()
@@ -160,10 +196,6 @@ func TestPanics(t *testing.T) {
 		assert.Equal(t, actual, expected)
 	}
 
-	check(`matcher within CatchAllVariable() may not accept unlimited path lengths`, func() {
-		pr.CatchAllVariable("first", pr.Choice(pr.CatchAllVariable("second", pr.Handlers(nil))))
-	})
-
 	check(`Choice() called without any matchers`, func() {
 		pr.Element("", pr.Choice())
 	})
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// RedirectingHandler returns an [http.Handler] that serves requests using the given [Matcher],
+// but redirects clients that use a non-canonical form of an existing endpoint path instead of rendering a "404 Not Found" response.
+//
+// Specifically, if a request path is not accepted by the matcher, but would be accepted after adding or removing a trailing slash,
+// the client is redirected to the accepted path with a "308 Permanent Redirect" response.
+// The query string is retained in the redirect target.
+// For example, with this matcher:
+//
+//	m := pr.Element("v1", pr.Choice(
+//		pr.Element("info", pr.Handlers(...)),
+//		pr.Element("objects", pr.Element("/", pr.Handlers(...))),
+//	))
+//
+// a request for "/v1/info/?verbose=1" will be redirected to "/v1/info?verbose=1",
+// and a request for "/v1/objects" will be redirected to "/v1/objects/".
+// Routes declared with [Here] already accept both forms, so no redirects are necessary for them.
+//
+// Redirects are only generated when the request path is not accepted as-is.
+// For this reason, wrapping a matcher with RedirectingHandler() never changes how already accepted request paths are handled.
+func RedirectingHandler(m Matcher) http.Handler {
+	return redirectingHandler{m.downcast(), false}
+}
+
+// CaseInsensitiveRedirectingHandler is like [RedirectingHandler],
+// but additionally redirects request paths that only differ from an acceptable path in the case of literal path elements.
+// For example, a request for "/V1/Objects/42" could be redirected to "/v1/objects/42".
+//
+// Only the literal path elements declared with [Element] are compared case-insensitively.
+// The values of path variables are retained as-is.
+func CaseInsensitiveRedirectingHandler(m Matcher) http.Handler {
+	return redirectingHandler{m.downcast(), true}
+}
+
+type redirectingHandler struct {
+	matcher  realMatcher
+	foldCase bool
+}
+
+// ServeHTTP implements the [http.Handler] interface.
+func (h redirectingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.matcher.TryServeHTTP(w, r) {
+		return
+	}
+
+	type candidate struct {
+		path     []string
+		foldCase bool
+	}
+	path := extractPath(r.URL)
+	candidates := []candidate{{toggleTrailingSlash(path), false}}
+	if h.foldCase {
+		candidates = append(candidates, candidate{path, true}, candidate{toggleTrailingSlash(path), true})
+	}
+
+	for _, c := range candidates {
+		if c.path == nil {
+			continue
+		}
+		s := matchState{
+			request:  r,
+			path:     c.path,
+			vars:     make(map[string]string),
+			foldCase: c.foldCase,
+		}
+		// NOTE: If matching succeeds with foldCase = true, Element() will have rewritten c.path into the canonical spelling.
+		if h.matcher.accept(c.path, &s) != nil {
+			target := "/" + strings.Join(c.path, "/")
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusPermanentRedirect)
+			return
+		}
+	}
+	h.matcher.renderNotFound(w, r)
+}
+
+// toggleTrailingSlash returns a copy of the given path (as returned by extractPath) with its trailing slash added or removed.
+// Returns nil for the root path, where a trailing slash cannot be toggled.
+func toggleTrailingSlash(path []string) []string {
+	switch {
+	case len(path) == 0 || (len(path) == 1 && path[0] == ""):
+		return nil
+	case path[len(path)-1] == "":
+		return slices.Clone(path[:len(path)-1])
+	default:
+		return append(slices.Clone(path), "")
+	}
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	pr "go.xyrillian.de/gg/pathrouter"
+)
+
+func TestRedirectingHandler(t *testing.T) {
+	h := func(msg string) pr.Matcher {
+		return pr.Handlers(pr.ByMethod{
+			http.MethodGet: func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+				fmt.Fprint(w, msg, vars["id"])
+			},
+		})
+	}
+	m := pr.Element("v1", pr.Choice(
+		pr.Element("info", h("info")),
+		pr.Element("objects", pr.Choice(
+			pr.Element("/", h("list")),
+			pr.Element("Special", h("special")),
+			pr.Variable("id", h("object ")),
+		)),
+		pr.Element("here", pr.Here(h("here"))),
+	))
+
+	check := func(handler http.Handler, path, expected string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "http://localhost"+path, http.NoBody)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		actual := fmt.Sprintf("%d: %s", rec.Code, strings.TrimSpace(rec.Body.String()))
+		if location := rec.Header().Get("Location"); location != "" {
+			actual = fmt.Sprintf("%d -> %s", rec.Code, location)
+		}
+		assert.Equal(t, actual, expected)
+	}
+
+	for _, handler := range []http.Handler{pr.RedirectingHandler(m), pr.CaseInsensitiveRedirectingHandler(m)} {
+		// accepted paths are served as-is
+		check(handler, "/v1/info", "200: info")
+		check(handler, "/v1/objects/", "200: list")
+		check(handler, "/v1/objects/Special", "200: special")
+		check(handler, "/v1/objects/special", "200: object special")
+		check(handler, "/v1/here", "200: here")
+		check(handler, "/v1/here/", "200: here")
+
+		// trailing slashes are toggled as necessary, and the query string is retained
+		check(handler, "/v1/info/", "308 -> /v1/info")
+		check(handler, "/v1/info/?verbose=1", "308 -> /v1/info?verbose=1")
+		check(handler, "/v1/objects", "308 -> /v1/objects/")
+		check(handler, "/v1/objects/42/", "308 -> /v1/objects/42")
+		check(handler, "/v1/objects/42%2F23/", "308 -> /v1/objects/42%2F23")
+
+		// paths that do not match even with toggled trailing slashes are still rejected
+		check(handler, "/", "404: 404 page not found")
+		check(handler, "/v1/unknown/", "404: 404 page not found")
+	}
+
+	// differences in case are only redirected by the case-insensitive variant
+	check(pr.RedirectingHandler(m), "/V1/Info", "404: 404 page not found")
+	check(pr.CaseInsensitiveRedirectingHandler(m), "/V1/Info", "308 -> /v1/info")
+	check(pr.CaseInsensitiveRedirectingHandler(m), "/V1/INFO/?foo=bar", "308 -> /v1/info?foo=bar")
+	check(pr.CaseInsensitiveRedirectingHandler(m), "/V1/objects/SPECIAL", "308 -> /v1/objects/Special") // first match wins, as usual
+	check(pr.CaseInsensitiveRedirectingHandler(m), "/V1/OBJECTS", "308 -> /v1/objects/")
+}
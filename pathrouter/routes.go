@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter
+
+import (
+	"slices"
+	"strings"
+)
+
+// Route describes a single endpoint path within a [Matcher] tree. It appears in the return value of func [Routes].
+type Route struct {
+	// A template for the request paths accepted by this route, e.g. "/v1/objects/:id" or "/v2/*repository/manifests/:reference".
+	// A [Variable] is shown as ":name", and a [CatchAllVariable] is shown as "*name".
+	// A subtree handled by [Mount] is shown as a single route ending in "/*".
+	//
+	// A trailing slash appears when the route was declared with Element("/").
+	// Routes declared with [Here] are shown without trailing slash, even though they also accept it.
+	Pattern string
+	// The request methods accepted by the handlers for this route, in sorted order (e.g. []string{"GET", "HEAD"}).
+	// For routes ending in [Mount], this is nil because any method may be accepted.
+	Methods []string
+	// The name given to this route by [Named], or the empty string if the route has no name.
+	Name string
+	// Conditions besides the request path that need to be satisfied for this route to match,
+	// e.g. `Host("{tenant}.example.com")` or `Header("X-Api-Version", "2")`, in the order in which they appear in the matcher tree.
+	Conditions []string
+}
+
+// Routes lists all endpoint paths that can be served by the given [Matcher] tree,
+// in the same order in which the matcher would try them.
+//
+// This is intended for generating documentation, or for checking in tests that all expected endpoints exist:
+//
+//	routes := pr.Routes(api.Handler())
+//	assert.Equal(t, routes, []pr.Route{
+//		{Pattern: "/v1/objects", Methods: []string{"GET", "HEAD"}},
+//		{Pattern: "/v1/objects/new", Methods: []string{"POST"}},
+//		{Pattern: "/v1/objects/:id", Methods: []string{"DELETE", "GET", "HEAD", "PATCH"}},
+//	})
+func Routes(m Matcher) []Route {
+	var result []Route
+	walkRoutes(m.downcast(), route{}, func(r route) {
+		result = append(result, r.export())
+	})
+	return result
+}
+
+// export converts into the public representation.
+func (r route) export() Route {
+	return Route{
+		Pattern:    r.pattern(),
+		Methods:    slices.Clone(r.methods),
+		Name:       r.name,
+		Conditions: r.conditions,
+	}
+}
+
+// route is the internal representation of type Route.
+type route struct {
+	segments   []segment
+	methods    []string
+	name       string
+	conditions []string
+	// The indexes of the options that were taken in each Choice(), from the outermost to the innermost Choice().
+	// Like in matchState.traceChoice, these refer to the flattened list of options.
+	choices []int
+}
+
+// segment is a single element of a route template.
+type segment struct {
+	// One of nodeElement, nodeVariable, nodeCatchAllVariable, nodeHere (for the optional trailing slash allowed by Here),
+	// or nodeMount (for the arbitrary subpath accepted by Mount).
+	kind      nodeKind
+	value     string
+	predicate func(string) bool
+}
+
+// walkRoutes calls yield for each route within the given matcher tree.
+// The argument `current` contains what was collected from the ancestors of `m`.
+func walkRoutes(m realMatcher, current route, yield func(route)) {
+	switch m.node.kind {
+	case nodeHandlers:
+		current.segments = slices.Clone(current.segments)
+		current.conditions = slices.Clone(current.conditions)
+		current.choices = slices.Clone(current.choices)
+		current.methods = m.node.methods
+		yield(current)
+	case nodeMount:
+		current.segments = append(slices.Clone(current.segments), segment{kind: nodeMount})
+		current.conditions = slices.Clone(current.conditions)
+		current.choices = slices.Clone(current.choices)
+		yield(current)
+	case nodeElement, nodeVariable, nodeCatchAllVariable, nodeHere:
+		current.segments = append(current.segments, segment{m.node.kind, m.node.value, m.node.predicate})
+		walkRoutes(m.node.children[0], current, yield)
+	case nodeChoice:
+		choices := current.choices
+		for idx, child := range flattenChoices(m.node.children) {
+			current.choices = append(choices, idx)
+			walkRoutes(child, current, yield)
+		}
+	case nodeNamed:
+		current.name = m.node.value
+		walkRoutes(m.node.children[0], current, yield)
+	case nodeWith, nodeConfig, nodeCORS, nodeWithRoute:
+		walkRoutes(m.node.children[0], current, yield)
+	case nodeCondition:
+		current.conditions = append(current.conditions, m.node.value)
+		walkRoutes(m.node.children[0], current, yield)
+	}
+}
+
+// pattern renders the format used in Route.Pattern.
+func (r route) pattern() string {
+	return r.render(func(s segment) string {
+		switch s.kind {
+		case nodeVariable:
+			return ":" + s.value
+		case nodeMount:
+			return "*"
+		default:
+			return "*" + s.value
+		}
+	})
+}
+
+// render builds a path template, using the given function to render each variable segment.
+func (r route) render(renderVariable func(segment) string) string {
+	var b strings.Builder
+	for _, s := range r.segments {
+		if s.kind == nodeHere {
+			continue
+		}
+		_ = b.WriteByte('/')
+		if s.kind == nodeElement {
+			_, _ = b.WriteString(s.value)
+		} else {
+			_, _ = b.WriteString(renderVariable(s))
+		}
+	}
+	if b.Len() == 0 {
+		return "/"
+	}
+	return b.String()
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	pr "go.xyrillian.de/gg/pathrouter"
+)
+
+func noop(w http.ResponseWriter, r *http.Request, vars map[string]string) {}
+
+func buildObjectsAPI() pr.Matcher {
+	return pr.Element("v1", pr.Element("objects", pr.Choice(
+		pr.Here(pr.Handlers(pr.ByMethod{
+			http.MethodGet: noop,
+		})),
+		pr.Element("new", pr.Here(pr.Handlers(pr.ByMethod{
+			http.MethodPost: noop,
+		}))),
+		pr.Variable("id", pr.Choice(
+			pr.Here(pr.Handlers(pr.ByMethod{
+				http.MethodDelete: noop,
+				http.MethodGet:    noop,
+				http.MethodPatch:  noop,
+			})),
+			pr.CatchAllVariable("path", pr.Element("blob", pr.Element("/", pr.Handlers(pr.ByMethod{
+				http.MethodPut: noop,
+			})))),
+		)),
+	)))
+}
+
+func TestRoutes(t *testing.T) {
+	assert.Equal(t, pr.Routes(buildObjectsAPI()), []pr.Route{
+		{Pattern: "/v1/objects", Methods: []string{"GET", "HEAD"}},
+		{Pattern: "/v1/objects/new", Methods: []string{"POST"}},
+		{Pattern: "/v1/objects/:id", Methods: []string{"DELETE", "GET", "HEAD", "PATCH"}},
+		{Pattern: "/v1/objects/:id/*path/blob/", Methods: []string{"PUT"}},
+	})
+
+	// check rendering of the root path
+	assert.Equal(t, pr.Routes(pr.Here(pr.Handlers(pr.ByMethod{http.MethodPost: noop}))), []pr.Route{
+		{Pattern: "/", Methods: []string{"POST"}},
+	})
+	assert.Equal(t, pr.Routes(pr.Element("/", pr.Handlers(pr.ByMethod{http.MethodPost: noop}))), []pr.Route{
+		{Pattern: "/", Methods: []string{"POST"}},
+	})
+}
+
+func TestWriteOpenAPI(t *testing.T) {
+	var buf strings.Builder
+	err := pr.WriteOpenAPI(&buf, buildObjectsAPI())
+	assert.ErrEqual(t, err, nil)
+	assert.Equal(t, buf.String(), strings.TrimSpace(`
+{
+  "openapi": "3.1.1",
+  "info": {
+    "title": "",
+    "version": ""
+  },
+  "paths": {
+    "/v1/objects": {
+      "get": {},
+      "head": {}
+    },
+    "/v1/objects/new": {
+      "post": {}
+    },
+    "/v1/objects/{id}": {
+      "delete": {},
+      "get": {},
+      "head": {},
+      "parameters": [
+        {
+          "name": "id",
+          "in": "path",
+          "required": true,
+          "schema": {
+            "type": "string"
+          }
+        }
+      ],
+      "patch": {}
+    },
+    "/v1/objects/{id}/{path}/blob/": {
+      "parameters": [
+        {
+          "name": "id",
+          "in": "path",
+          "required": true,
+          "schema": {
+            "type": "string"
+          }
+        },
+        {
+          "name": "path",
+          "in": "path",
+          "required": true,
+          "schema": {
+            "type": "string"
+          }
+        }
+      ],
+      "put": {}
+    }
+  }
+}
+	`)+"\n")
+}
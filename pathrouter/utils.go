@@ -20,3 +20,7 @@ func pathUnescape(in string) string {
 func increment(x int) int {
 	return x + 1
 }
+
+func decrement(x int) int {
+	return x - 1
+}
@@ -0,0 +1,405 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	. "go.xyrillian.de/gg/option"
+	"go.xyrillian.de/gg/options"
+)
+
+// Problem describes a mistake in the structure of a [Matcher] tree. It appears in the return value of func [Validate].
+type Problem struct {
+	// The route that is affected by this problem.
+	Route Route
+	// A description of the problem, e.g. `shadowed by earlier route "/v1/objects/:id"`.
+	Message string
+}
+
+// String returns a human-readable representation of this problem, including the pattern of the affected route.
+func (p Problem) String() string {
+	return fmt.Sprintf("route %q: %s", p.Route.Pattern, p.Message)
+}
+
+// Validate checks the given [Matcher] tree for routes that can never be reached, or that conflict with earlier routes. Specifically, it reports:
+//
+//   - routes that can never match any request path, e.g. Element("/", Element("foo", ...)),
+//     because a trailing slash cannot be followed by another path element;
+//   - routes that can never match because of the number of path elements that they accept,
+//     e.g. Handlers() at the root of the matcher tree, since even the root path "/" has one (empty) path element
+//     (use Here() instead);
+//   - routes whose request paths are all accepted by an earlier route, since [Choice] uses first-match-wins.
+//     If both routes have handlers for the same methods, the message calls this out as duplicate handlers;
+//   - routes whose request paths are partially accepted by an earlier route with handlers for the same methods,
+//     e.g. IntVariable("id", ...) followed by Variable("id", ...).
+//     Since it is common to place routes with literal path elements before more general routes
+//     (e.g. Element("new", ...) before Variable("id", ...)), and to place a catch-all [Mount] after other routes,
+//     partial overlaps involving literal path elements of the earlier route or a Mount() in the later route are not reported.
+//
+// The most common example of a shadowed route is a [Variable] that appears before an [Element] in the same Choice():
+//
+//	m := pr.Element("objects", pr.Choice(
+//		pr.Variable("id", pr.Handlers(...)),
+//		pr.Element("new", pr.Handlers(...)), // shadowed: "/objects/new" is accepted by the previous option
+//	))
+//
+// Note that an earlier route wins regardless of request method:
+// If the earlier route does not have a handler for the request method, it will render a "405 Method Not Allowed" response.
+//
+// The analysis is conservative: It treats a [VariableMatching] as potentially accepting any value, except when compared with literal path elements,
+// and two VariableMatching() are never considered to accept the same value, since their predicates cannot be compared.
+// Partially shadowed routes are only reported if both routes have handlers for the same methods.
+// Similarly, an earlier route with a condition like [Host] or [Header] only shadows (or partially shadows) later routes that have the same condition.
+//
+// Validate assumes that it is given the root of the matcher tree.
+//
+// Validate is intended to be used in unit tests:
+//
+//	assert.Equal(t, pr.Validate(api.Handler()), nil)
+func Validate(m Matcher) []Problem {
+	var (
+		routes []route
+		result []Problem
+	)
+	walkRoutes(m.downcast(), route{}, func(r route) {
+		routes = append(routes, r)
+	})
+	// at the root, the path has at least one element (see func extractPath)
+	var lengthProblems []lengthProblem
+	findLengthProblems(m.downcast(), 1, None[int](), nil, func(p lengthProblem) {
+		lengthProblems = append(lengthProblems, p)
+	})
+
+	possible := make([]bool, len(routes))
+	for idx, r := range routes {
+		possible[idx] = r.isPossible()
+		if !possible[idx] {
+			result = append(result, Problem{r.export(), `can never match because Element("/") is followed by further path elements`})
+			continue
+		}
+		for _, p := range lengthProblems {
+			if len(r.choices) >= len(p.choices) && slices.Equal(r.choices[:len(p.choices)], p.choices) {
+				possible[idx] = false
+				result = append(result, Problem{r.export(), p.message})
+				break
+			}
+		}
+	}
+
+	for idx, r := range routes {
+		if !possible[idx] {
+			continue
+		}
+		if problem, ok := r.findShadowing(routes[:idx], possible[:idx]).Unpack(); ok {
+			result = append(result, problem)
+		}
+	}
+	return result
+}
+
+// findShadowing reports if this route is shadowed by one of the given earlier routes.
+// Full shadowing takes precedence over partial shadowing, regardless of the order of the earlier routes.
+func (r route) findShadowing(earlier []route, possible []bool) Option[Problem] {
+	for idx, prev := range earlier {
+		if !possible[idx] || !prev.shadows(r) {
+			continue
+		}
+		duplicateMethods := r.duplicateMethods(prev)
+		var msg string
+		if len(duplicateMethods) > 0 {
+			msg = fmt.Sprintf("duplicate handlers for %s: all requests are already accepted by earlier route %q",
+				strings.Join(duplicateMethods, ", "), prev.pattern())
+		} else {
+			msg = fmt.Sprintf("shadowed by earlier route %q", prev.pattern())
+		}
+		return Some(Problem{r.export(), msg})
+	}
+
+	for idx, prev := range earlier {
+		if !possible[idx] || !prev.overlaps(r) {
+			continue
+		}
+		duplicateMethods := r.duplicateMethods(prev)
+		if len(duplicateMethods) > 0 {
+			msg := fmt.Sprintf("overlapping handlers for %s: some requests are already accepted by earlier route %q",
+				strings.Join(duplicateMethods, ", "), prev.pattern())
+			return Some(Problem{r.export(), msg})
+		}
+	}
+	return None[Problem]()
+}
+
+// lengthProblem appears in func findLengthProblems.
+type lengthProblem struct {
+	// Identifies the affected subtree: All routes whose route.choices start with this prefix are affected.
+	choices []int
+	message string
+}
+
+// findLengthProblems reports subtrees of the matcher tree that can never match because the path lengths that they accept
+// (as tracked in realMatcher.minLength and realMatcher.maxLength) do not overlap with the path lengths that can reach them.
+// The arguments minAvailable and maxAvailable describe the range of path lengths that can reach `m`.
+func findLengthProblems(m realMatcher, minAvailable int, maxAvailable Option[int], choices []int, yield func(lengthProblem)) {
+	tooLong := maxAvailable.IsSomeAnd(func(maxAvailable int) bool { return m.minLength > maxAvailable })
+	tooShort := m.maxLength.IsSomeAnd(func(maxLength int) bool { return maxLength < minAvailable })
+	if tooLong || tooShort {
+		yield(lengthProblem{slices.Clone(choices), fmt.Sprintf("can never match because it requires %s, but the remaining request path has %s",
+			formatLengthRange(m.minLength, m.maxLength), formatLengthRange(minAvailable, maxAvailable))})
+		return
+	}
+
+	switch m.node.kind {
+	case nodeElement, nodeVariable:
+		findLengthProblems(m.node.children[0], max(minAvailable-1, 0), options.Map(maxAvailable, decrement), choices, yield)
+	case nodeCatchAllVariable:
+		findLengthProblems(m.node.children[0], 0, options.Map(maxAvailable, decrement), choices, yield)
+	case nodeHere:
+		findLengthProblems(m.node.children[0], 0, Some(0), choices, yield)
+	case nodeChoice:
+		for idx, child := range flattenChoices(m.node.children) {
+			findLengthProblems(child, minAvailable, maxAvailable, append(choices, idx), yield)
+		}
+	case nodeNamed, nodeWith, nodeConfig, nodeCORS, nodeWithRoute, nodeCondition:
+		findLengthProblems(m.node.children[0], minAvailable, maxAvailable, choices, yield)
+	default:
+	}
+}
+
+// formatLengthRange renders a range of path lengths for use in a Problem message, e.g. "at least 1 path element".
+func formatLengthRange(minLength int, maxLength Option[int]) string {
+	noun := func(count int) string {
+		if count == 1 {
+			return "path element"
+		}
+		return "path elements"
+	}
+	switch value, ok := maxLength.Unpack(); {
+	case !ok:
+		return fmt.Sprintf("at least %d %s", minLength, noun(minLength))
+	case value == minLength:
+		return fmt.Sprintf("exactly %d %s", minLength, noun(minLength))
+	default:
+		return fmt.Sprintf("between %d and %d path elements", minLength, value)
+	}
+}
+
+// isPossible returns false if this route can never match any request path.
+func (r route) isPossible() bool {
+	// since extractPath() normalizes consecutive slashes, an empty path element can only appear at the end of the path
+	for idx, s := range r.segments[:max(len(r.segments)-1, 0)] {
+		if s.kind == nodeElement && s.value == "" {
+			switch r.segments[idx+1].kind {
+			case nodeElement, nodeVariable, nodeCatchAllVariable:
+				return false
+			default:
+			}
+		}
+	}
+	return true
+}
+
+// shadows returns true if every request accepted by `other` would already be accepted by `r`.
+func (r route) shadows(other route) bool {
+	for _, condition := range r.conditions {
+		if !slices.Contains(other.conditions, condition) {
+			return false
+		}
+	}
+
+	// Here() matches either with or without trailing slash, so `r` needs to accept both alternatives of `other`,
+	// whereas it is sufficient if one alternative of `r` accepts an alternative of `other`
+	for _, otherSegments := range other.segmentAlternatives() {
+		covered := false
+		for _, segments := range r.segmentAlternatives() {
+			if segmentsCover(segments, otherSegments) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// overlaps returns true if some request accepted by `other` would already be accepted by `r`,
+// ignoring the typically intentional overlaps described in the documentation of func Validate.
+func (r route) overlaps(other route) bool {
+	for _, condition := range r.conditions {
+		if !slices.Contains(other.conditions, condition) {
+			return false
+		}
+	}
+
+	for _, otherSegments := range other.segmentAlternatives() {
+		for _, segments := range r.segmentAlternatives() {
+			if segmentsOverlap(segments, false, otherSegments, false) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// segmentAlternatives returns the route's segments, with Here() replaced by either nothing or Element("/").
+func (r route) segmentAlternatives() [][]segment {
+	idx := slices.IndexFunc(r.segments, func(s segment) bool { return s.kind == nodeHere })
+	if idx == -1 {
+		return [][]segment{r.segments}
+	}
+	withoutSlash := slices.Delete(slices.Clone(r.segments), idx, idx+1)
+	withSlash := slices.Clone(r.segments)
+	withSlash[idx] = segment{kind: nodeElement, value: ""}
+	return [][]segment{withoutSlash, withSlash}
+}
+
+// segmentsCover returns true if every path accepted by the segments `b` is also accepted by the segments `a`.
+// Neither argument may contain segments of kind nodeHere.
+func segmentsCover(a, b []segment) bool {
+	if len(a) == 0 {
+		return len(b) == 0
+	}
+	switch a[0].kind {
+	case nodeMount:
+		return true
+	case nodeCatchAllVariable:
+		// a catch-all variable can absorb any nonzero number of the leading segments of `b`
+		for length := 1; length <= len(b); length++ {
+			if b[length-1].kind == nodeMount {
+				return false
+			}
+			if segmentsCover(a[1:], b[length:]) {
+				return true
+			}
+		}
+		return false
+	default:
+	}
+
+	if len(b) == 0 {
+		return false
+	}
+	var ok bool
+	switch a[0].kind {
+	case nodeElement:
+		ok = b[0].kind == nodeElement && b[0].value == a[0].value
+	case nodeVariable:
+		switch b[0].kind {
+		case nodeElement:
+			ok = b[0].value != "" && (a[0].predicate == nil || a[0].predicate(pathUnescape(b[0].value)))
+		case nodeVariable:
+			ok = a[0].predicate == nil
+		default:
+		}
+	default:
+	}
+	return ok && segmentsCover(a[1:], b[1:])
+}
+
+// segmentsOverlap returns true if at least one path is accepted by both the segments `a` (of the earlier route) and the segments `b` (of the later route).
+// As explained in the documentation of func Validate, overlaps are ignored when they involve an Element() in `a` or a Mount() in `b`.
+// Neither argument may contain segments of kind nodeHere.
+// The flags indicate whether the respective side is within a CatchAllVariable() that has already consumed at least one path element,
+// and can thus consume further path elements before continuing with its segments.
+func segmentsOverlap(a []segment, inCatchAllA bool, b []segment, inCatchAllB bool) bool {
+	switch {
+	case len(b) > 0 && b[0].kind == nodeMount:
+		return false
+	case len(a) > 0 && a[0].kind == nodeMount:
+		return true
+	case len(a) == 0 && len(b) == 0:
+		return true
+	default:
+	}
+
+	// try all ways of consuming the next path element on both sides
+	for _, stepA := range nextSegmentSteps(a, inCatchAllA) {
+		for _, stepB := range nextSegmentSteps(b, inCatchAllB) {
+			// if neither side advances in its segments, the recursion would not terminate
+			if stepA.stay && stepB.stay {
+				continue
+			}
+			if segmentsAcceptSameElement(stepA.segment, stepB.segment) && segmentsOverlap(stepA.rest, stepA.inCatchAll, stepB.rest, stepB.inCatchAll) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// segmentStep appears in func nextSegmentSteps.
+type segmentStep struct {
+	segment    segment   // the segment consuming the next path element
+	rest       []segment // the segments for the remaining path elements
+	inCatchAll bool      // whether rest[0] is preceded by a CatchAllVariable() that can consume further path elements
+	stay       bool      // whether `rest` is the same as before this step
+}
+
+// nextSegmentSteps returns all possible ways for the given segments to consume the next path element (see func segmentsOverlap).
+func nextSegmentSteps(segments []segment, inCatchAll bool) []segmentStep {
+	var result []segmentStep
+	if inCatchAll {
+		result = append(result, segmentStep{segment{kind: nodeCatchAllVariable}, segments, true, true})
+	}
+	if len(segments) > 0 {
+		switch segments[0].kind {
+		case nodeElement, nodeVariable:
+			result = append(result, segmentStep{segments[0], segments[1:], false, false})
+		case nodeCatchAllVariable:
+			result = append(result, segmentStep{segments[0], segments[1:], true, false})
+		default:
+		}
+	}
+	return result
+}
+
+// segmentsAcceptSameElement returns true if at least one path element is accepted by both segments,
+// which must be of kind nodeElement, nodeVariable or nodeCatchAllVariable.
+// Like in func segmentsOverlap, an Element() in `a` is only considered if `b` is an Element() with the same value.
+func segmentsAcceptSameElement(a, b segment) bool {
+	switch {
+	case a.kind == nodeElement:
+		return b.kind == nodeElement && a.value == b.value
+	case b.kind == nodeElement:
+		return segmentAcceptsElement(a, b.value)
+	default:
+		// predicates cannot be compared, so two variables with predicates are conservatively assumed to accept disjoint values
+		return a.predicate == nil || b.predicate == nil
+	}
+}
+
+// segmentAcceptsElement returns true if the given segment of kind nodeVariable or nodeCatchAllVariable accepts the given path element.
+func segmentAcceptsElement(s segment, value string) bool {
+	if s.kind == nodeCatchAllVariable {
+		return true
+	}
+	return value != "" && (s.predicate == nil || s.predicate(pathUnescape(value)))
+}
+
+// duplicateMethods returns which methods of this route also have handlers in the other route.
+func (r route) duplicateMethods(other route) []string {
+	// routes ending in Mount() accept all methods
+	if other.isMount() {
+		return r.methods
+	}
+	if r.isMount() {
+		return other.methods
+	}
+	var result []string
+	for _, method := range r.methods {
+		if slices.Contains(other.methods, method) {
+			result = append(result, method)
+		}
+	}
+	return result
+}
+
+// isMount returns true if this route ends in Mount().
+func (r route) isMount() bool {
+	return len(r.segments) > 0 && r.segments[len(r.segments)-1].kind == nodeMount
+}
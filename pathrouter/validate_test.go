@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	pr "go.xyrillian.de/gg/pathrouter"
+)
+
+func TestValidate(t *testing.T) {
+	// a well-formed matcher tree does not have any problems
+	assert.Equal(t, pr.Validate(buildObjectsAPI()), nil)
+
+	get := pr.ByMethod{http.MethodGet: noop}
+	post := pr.ByMethod{http.MethodPost: noop}
+	m := pr.Choice(
+		pr.Element("objects", pr.Choice(
+			pr.Variable("id", pr.Handlers(get)),
+			pr.Element("new", pr.Handlers(post)),               // shadowed by Variable("id")
+			pr.IntVariable("num", pr.Handlers(get)),            // duplicate of Variable("id")
+			pr.Element("/", pr.Element("x", pr.Handlers(get))), // impossible
+		)),
+		pr.Element("files", pr.Choice(
+			pr.IntVariable("id", pr.Handlers(get)),
+			pr.Element("42", pr.Handlers(get)),   // shadowed since the predicate accepts "42"
+			pr.Element("new", pr.Handlers(get)),  // not shadowed since the predicate does not accept "new"
+			pr.Variable("id", pr.Handlers(post)), // not shadowed since IntVariable() does not accept every value
+		)),
+		pr.Element("blobs", pr.Choice(
+			pr.CatchAllVariable("path", pr.Element("info", pr.Here(pr.Handlers(get)))),
+			pr.Element("foo", pr.Variable("id", pr.Element("info", pr.Handlers(get)))), // shadowed by CatchAllVariable()
+			pr.Element("info", pr.Element("/", pr.Handlers(get))),                      // not shadowed since the catch-all variable needs at least one element
+			pr.Element("foo", pr.Element("info", pr.Element("/", pr.Handlers(get)))),   // shadowed since Here() also accepts a trailing slash
+		)),
+		pr.Element("static", pr.Choice(
+			pr.Header("X-Foo", "bar", pr.Mount(http.NotFoundHandler())),
+			pr.Element("index.html", pr.Handlers(get)), // not shadowed since the Mount() has a condition
+			pr.Mount(http.NotFoundHandler()),
+			pr.Here(pr.Handlers(get)), // shadowed by Mount()
+		)),
+	)
+
+	var messages []string
+	for _, p := range pr.Validate(m) {
+		messages = append(messages, p.String())
+	}
+	assert.Equal(t, messages, []string{
+		`route "/objects//x": can never match because Element("/") is followed by further path elements`,
+		`route "/objects/new": shadowed by earlier route "/objects/:id"`,
+		`route "/objects/:num": duplicate handlers for GET, HEAD: all requests are already accepted by earlier route "/objects/:id"`,
+		`route "/files/42": duplicate handlers for GET, HEAD: all requests are already accepted by earlier route "/files/:id"`,
+		`route "/blobs/foo/:id/info": duplicate handlers for GET, HEAD: all requests are already accepted by earlier route "/blobs/*path/info"`,
+		`route "/blobs/foo/info/": duplicate handlers for GET, HEAD: all requests are already accepted by earlier route "/blobs/*path/info"`,
+		`route "/static": duplicate handlers for GET, HEAD: all requests are already accepted by earlier route "/static/*"`,
+	})
+
+	// the affected route is reported in full
+	assert.Equal(t, pr.Validate(pr.Choice(pr.Here(pr.Handlers(get)), pr.Named("second", pr.Here(pr.Handlers(post))))), []pr.Problem{{
+		Route:   pr.Route{Pattern: "/", Methods: []string{"POST"}, Name: "second"},
+		Message: `shadowed by earlier route "/"`,
+	}})
+
+	// routes that can never match because of the number of path elements that they accept
+	assert.Equal(t, pr.Validate(pr.Choice(
+		pr.Named("root", pr.Handlers(get)), // even the root path "/" has one path element, so Here() is required
+		pr.Here(pr.Handlers(post)),
+	)), []pr.Problem{{
+		Route:   pr.Route{Pattern: "/", Methods: []string{"GET", "HEAD"}, Name: "root"},
+		Message: "can never match because it requires exactly 0 path elements, but the remaining request path has at least 1 path element",
+	}})
+
+	// routes that partially overlap with earlier routes with handlers for the same methods
+	m = pr.Choice(
+		pr.Element("objects", pr.Choice(
+			pr.IntVariable("id", pr.Handlers(get)),
+			pr.Element("new", pr.Handlers(get)), // not reported since the predicate does not accept "new"
+			pr.Variable("id", pr.Handlers(get)), // overlaps with IntVariable("id") for GET
+		)),
+		pr.Element("files", pr.Choice(
+			pr.Element("new", pr.Element("info", pr.Handlers(get))),           // literal path elements before variables are intentional...
+			pr.Variable("id", pr.Element("info", pr.Handlers(get))),           // ...so this is not reported
+			pr.CatchAllVariable("path", pr.Element("info", pr.Handlers(get))), // overlaps with Variable("id") for GET
+			pr.Mount(http.NotFoundHandler()),                                  // not reported: a catch-all Mount() after other routes is intentional
+		)),
+		pr.Element("blobs", pr.Choice(
+			pr.VariableMatching("id", isLowercaseWord, pr.Handlers(get)),
+			pr.IntVariable("id", pr.Handlers(get)), // not reported since the predicates cannot be compared
+		)),
+		pr.Element("users", pr.Choice(
+			pr.Variable("id", pr.Handlers(get)),
+			pr.VariableMatching("id", isLowercaseWord, pr.Handlers(get)), // fully shadowed by Variable("id")
+		)),
+	)
+	messages = nil
+	for _, p := range pr.Validate(m) {
+		messages = append(messages, p.String())
+	}
+	assert.Equal(t, messages, []string{
+		`route "/objects/:id": overlapping handlers for GET, HEAD: some requests are already accepted by earlier route "/objects/:id"`,
+		`route "/files/*path/info": overlapping handlers for GET, HEAD: some requests are already accepted by earlier route "/files/:id/info"`,
+		`route "/users/:id": duplicate handlers for GET, HEAD: all requests are already accepted by earlier route "/users/:id"`,
+	})
+}
@@ -3,29 +3,113 @@
 
 package pathrouter
 
-import "go.xyrillian.de/gg/options"
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"go.xyrillian.de/gg/options"
+)
 
 // Variable is a [Matcher] that accepts subpaths with at least one path element.
 // The value of the first path element will be collected into vars[name],
 // and the remaining subpath will have to be accepted by the next matcher.
+//
+// Any non-empty value is accepted. To restrict which values are accepted,
+// use [VariableMatching] or one of the typed variants like [IntVariable] instead.
 func Variable(name string, matcher Matcher) Matcher {
-	return variable(name, matcher.downcast())
+	return variable(name, nil, matcher.downcast())
+}
+
+// VariableMatching is like [Variable], but only accepts values for which the predicate returns true.
+// The predicate is called with the unescaped value.
+//
+// If the predicate returns false, the matcher declines to handle the request.
+// When VariableMatching() appears within [Choice], the next option will be tried;
+// otherwise the request will usually end in a "404 Not Found" response.
+func VariableMatching(name string, predicate func(string) bool, matcher Matcher) Matcher {
+	if predicate == nil {
+		panic("VariableMatching() called with predicate = nil")
+	}
+	return variable(name, predicate, matcher.downcast())
+}
+
+// IntVariable is like [Variable], but only accepts decimal integer values that fit into an int64.
+// The parsed value can be obtained with func [IntValue].
+func IntVariable(name string, matcher Matcher) Matcher {
+	return variable(name, isInt, matcher.downcast())
 }
 
-func variable(name string, matcher realMatcher) Matcher {
+// UUIDVariable is like [Variable], but only accepts UUIDs in their canonical textual representation
+// (e.g. "a0ee0e0e-14ea-4cbf-ae4d-1f1d5e4f7c8b", in either lowercase or uppercase).
+// The parsed value can be obtained with func [UUIDValue].
+func UUIDVariable(name string, matcher Matcher) Matcher {
+	return variable(name, isUUID, matcher.downcast())
+}
+
+func variable(name string, predicate func(string) bool, matcher realMatcher) Matcher {
 	return realMatcher{
 		minLength: matcher.minLength + 1,
 		maxLength: options.Map(matcher.maxLength, increment),
-		accept: func(path []string, vars map[string]string) HandlerFunc {
+		node:      node{kind: nodeVariable, value: name, predicate: predicate, children: []realMatcher{matcher}},
+		accept: func(path []string, s *matchState) HandlerFunc {
 			if len(path) == 0 || path[0] == "" {
 				return nil
 			}
-			handlerFunc := matcher.accept(path[1:], vars)
+			value := pathUnescape(path[0])
+			if predicate != nil && !predicate(value) {
+				return nil
+			}
+			handlerFunc := matcher.accept(path[1:], s)
 			if handlerFunc == nil {
 				return nil
 			}
-			vars[name] = pathUnescape(path[0])
+			s.vars[name] = value
 			return handlerFunc
 		},
 	}
 }
+
+func isInt(value string) bool {
+	_, err := strconv.ParseInt(value, 10, 64)
+	return err == nil
+}
+
+func isUUID(value string) bool {
+	_, ok := parseUUID(value)
+	return ok
+}
+
+func parseUUID(value string) (result [16]byte, ok bool) {
+	if len(value) != 36 || value[8] != '-' || value[13] != '-' || value[18] != '-' || value[23] != '-' {
+		return result, false
+	}
+	hexStr := value[0:8] + value[9:13] + value[14:18] + value[19:23] + value[24:36]
+	_, err := hex.Decode(result[:], []byte(hexStr))
+	return result, err == nil
+}
+
+// IntValue returns the value of a variable that was declared with [IntVariable].
+//
+// Since the value was already validated during routing, this function does not return an error.
+// It panics if the variable does not exist or was not declared with [IntVariable].
+func IntValue(vars map[string]string, name string) int64 {
+	value, err := strconv.ParseInt(vars[name], 10, 64)
+	if err != nil {
+		panic(fmt.Sprintf("IntValue() called on variable %q, which was not declared with IntVariable()", name))
+	}
+	return value
+}
+
+// UUIDValue returns the value of a variable that was declared with [UUIDVariable], as an array of 16 bytes.
+// This representation is compatible with widespread UUID libraries like github.com/google/uuid.
+//
+// Since the value was already validated during routing, this function does not return an error.
+// It panics if the variable does not exist or was not declared with [UUIDVariable].
+func UUIDValue(vars map[string]string, name string) [16]byte {
+	value, ok := parseUUID(vars[name])
+	if !ok {
+		panic(fmt.Sprintf("UUIDValue() called on variable %q, which was not declared with UUIDVariable()", name))
+	}
+	return value
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	pr "go.xyrillian.de/gg/pathrouter"
+)
+
+func TestTypedVariables(t *testing.T) {
+	m := pr.Element("objects", pr.Choice(
+		pr.IntVariable("id", pr.Named("by-id", pr.Handlers(pr.ByMethod{
+			http.MethodGet: func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+				fmt.Fprintf(w, "object with ID %d", pr.IntValue(vars, "id"))
+			},
+		}))),
+		pr.UUIDVariable("uuid", pr.Handlers(pr.ByMethod{
+			http.MethodGet: func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+				fmt.Fprintf(w, "object with UUID %x", pr.UUIDValue(vars, "uuid"))
+			},
+		})),
+		pr.VariableMatching("name", isLowercaseWord, pr.Named("by-name", pr.Handlers(pr.ByMethod{
+			http.MethodGet: func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+				fmt.Fprintf(w, "object with name %s", vars["name"])
+			},
+		}))),
+	))
+	check := func(path, expected string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "http://localhost"+path, http.NoBody)
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, req)
+		assert.Equal(t, fmt.Sprintf("%d: %s", rec.Code, strings.TrimSpace(rec.Body.String())), expected)
+	}
+
+	check("/objects/42", "200: object with ID 42")
+	check("/objects/-42", "200: object with ID -42")
+	check("/objects/A0EE0E0E-14EA-4CBF-AE4D-1F1D5E4F7C8B", "200: object with UUID a0ee0e0e14ea4cbfae4d1f1d5e4f7c8b")
+	check("/objects/a0ee0e0e-14ea-4cbf-ae4d-1f1d5e4f7c8b", "200: object with UUID a0ee0e0e14ea4cbfae4d1f1d5e4f7c8b")
+	check("/objects/foo", "200: object with name foo")
+	check("/objects/fo%6F", "200: object with name foo") // predicate sees unescaped value
+
+	// values that are rejected by all variables
+	check("/objects/99999999999999999999", "404: 404 page not found")
+	check("/objects/a0ee0e0e-14ea-4cbf-ae4d-1f1d5e4f7c8", "404: 404 page not found")
+	check("/objects/a0ee0e0e_14ea_4cbf_ae4d_1f1d5e4f7c8b", "404: 404 page not found")
+	check("/objects/Foo", "404: 404 page not found")
+
+	// URLFor() also checks variable formats
+	_, err := pr.URLFor(m, "by-id", map[string]string{"id": "foo"})
+	assert.ErrEqual(t, err, `cannot build URL for route "by-id": value for variable "id" is not acceptable: "foo"`)
+	url, err := pr.URLFor(m, "by-name", map[string]string{"name": "foo"})
+	assert.ErrEqual(t, err, nil)
+	assert.Equal(t, url, "/objects/foo")
+
+	// check panics
+	msg := assert.PanicsWith[string](t, func() { pr.VariableMatching("name", nil, pr.Handlers(nil)) })
+	assert.Equal(t, msg, `VariableMatching() called with predicate = nil`)
+	msg = assert.PanicsWith[string](t, func() { pr.IntValue(map[string]string{"id": "foo"}, "id") })
+	assert.Equal(t, msg, `IntValue() called on variable "id", which was not declared with IntVariable()`)
+	msg = assert.PanicsWith[string](t, func() { pr.UUIDValue(map[string]string{}, "id") })
+	assert.Equal(t, msg, `UUIDValue() called on variable "id", which was not declared with UUIDVariable()`)
+}
+
+func isLowercaseWord(value string) bool {
+	return strings.Trim(value, "abcdefghijklmnopqrstuvwxyz") == ""
+}
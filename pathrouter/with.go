@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter
+
+// With is a [Matcher] that behaves exactly like the given matcher,
+// but wraps every [HandlerFunc] within it in the given middleware.
+// This can be used to apply e.g. authentication, logging or rate limiting to a whole subtree of the routing table:
+//
+//	m := pr.Choice(
+//		pr.Element("public", publicAPI),
+//		pr.Element("admin", pr.With(requireAdminToken, adminAPI)),
+//	)
+//
+//	func requireAdminToken(next pr.HandlerFunc) pr.HandlerFunc {
+//		return func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+//			if !isAdminToken(r.Header.Get("X-Auth-Token")) {
+//				http.Error(w, "unauthorized", http.StatusUnauthorized)
+//				return
+//			}
+//			next(w, r, vars)
+//		}
+//	}
+//
+// The middleware is only invoked for requests that are accepted by the given matcher.
+// The [HandlerFunc] returned by the middleware receives all variables extracted from the request path,
+// including those that were extracted by matchers outside of With().
+// This also applies to responses generated by [Handlers] itself, e.g. "405 Method Not Allowed".
+//
+// When With() calls are nested, middlewares compose outermost-first,
+// i.e. With(a, With(b, m)) invokes a before b.
+//
+// Since the middleware is invoked anew for each request, it should be cheap to call.
+// Expensive preparations should be done once outside the middleware function.
+func With(middleware func(HandlerFunc) HandlerFunc, matcher Matcher) Matcher {
+	return with(middleware, matcher.downcast())
+}
+
+func with(middleware func(HandlerFunc) HandlerFunc, matcher realMatcher) Matcher {
+	if middleware == nil {
+		panic("With() called with middleware = nil")
+	}
+
+	return realMatcher{
+		minLength: matcher.minLength,
+		maxLength: matcher.maxLength,
+		notFound:  matcher.notFound,
+		node:      node{kind: nodeWith, children: []realMatcher{matcher}},
+		accept: func(path []string, s *matchState) HandlerFunc {
+			handlerFunc := matcher.accept(path, s)
+			if handlerFunc == nil {
+				return nil
+			}
+			return middleware(handlerFunc)
+		},
+	}
+}
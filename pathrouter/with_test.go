@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	pr "go.xyrillian.de/gg/pathrouter"
+)
+
+func TestWith(t *testing.T) {
+	var log []string
+	logging := func(prefix string) func(pr.HandlerFunc) pr.HandlerFunc {
+		return func(next pr.HandlerFunc) pr.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+				log = append(log, fmt.Sprintf("%s: %s %s with id = %q", prefix, r.Method, r.URL.Path, vars["id"]))
+				next(w, r, vars)
+			}
+		}
+	}
+	handle := func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		log = append(log, "handler")
+	}
+
+	m := pr.With(logging("outer"), pr.Choice(
+		pr.Element("public", pr.Handlers(pr.ByMethod{http.MethodGet: handle})),
+		pr.Variable("id", pr.Element("admin", pr.With(logging("inner"), pr.Handlers(pr.ByMethod{
+			http.MethodPost: handle,
+		})))),
+	))
+	check := func(method, path string, expectedLog ...string) {
+		t.Helper()
+		log = nil
+		req := httptest.NewRequest(method, "http://localhost"+path, http.NoBody)
+		m.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, strings.Join(log, "\n"), strings.Join(expectedLog, "\n"))
+	}
+
+	check("GET", "/public",
+		`outer: GET /public with id = ""`,
+		`handler`,
+	)
+	check("POST", "/42/admin",
+		`outer: POST /42/admin with id = "42"`,
+		`inner: POST /42/admin with id = "42"`,
+		`handler`,
+	)
+	// middleware also wraps the 405 response from Handlers()
+	check("GET", "/42/admin",
+		`outer: GET /42/admin with id = "42"`,
+		`inner: GET /42/admin with id = "42"`,
+	)
+	// middleware is not invoked for requests that are not matched
+	check("GET", "/42/public")
+
+	// With() is transparent to introspection
+	assert.Equal(t, pr.Routes(m), []pr.Route{
+		{Pattern: "/public", Methods: []string{"GET", "HEAD"}},
+		{Pattern: "/:id/admin", Methods: []string{"POST"}},
+	})
+
+	msg := assert.PanicsWith[string](t, func() { pr.With(nil, pr.Handlers(nil)) })
+	assert.Equal(t, msg, `With() called with middleware = nil`)
+}
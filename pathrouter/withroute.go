@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter
+
+import (
+	"context"
+	"encoding/binary"
+	"net/http"
+	"slices"
+
+	. "go.xyrillian.de/gg/option"
+)
+
+// WithRoute is like [With], but the middleware also receives the [Route] that the request was matched to.
+// This is intended for access logs and request metrics, which should be labeled with the route pattern (e.g. "/v1/objects/:id")
+// instead of the raw request path, since the latter would lead to unbounded cardinality:
+//
+//	m := pr.WithRoute(logRequests, apiMatcher)
+//
+//	func logRequests(route pr.Route, next pr.HandlerFunc) pr.HandlerFunc {
+//		return func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+//			next(w, r, vars)
+//			log.Printf("%s %s (route %q)", r.Method, r.URL.Path, route.Pattern)
+//		}
+//	}
+//
+// The middleware receives the same value that func [Routes] reports for this route.
+// Patterns are relative to the position of WithRoute() within the matcher tree,
+// so WithRoute() should usually be applied to the root of the matcher tree to report full patterns.
+// The Route must not be modified, since it is shared between all requests for the same route.
+//
+// Handlers (and middlewares given to [With]) within WithRoute() can obtain the same Route through func [MatchedRoute].
+// For a ready-made middleware that reports request metrics, see type [RequestMetrics].
+func WithRoute(middleware func(Route, HandlerFunc) HandlerFunc, matcher Matcher) Matcher {
+	if middleware == nil {
+		panic("WithRoute() called with middleware = nil")
+	}
+	inner := matcher.downcast()
+
+	// Since Choice() is the only matcher with multiple children, each route is uniquely identified by which options were taken in each Choice().
+	routes := make(map[string]*Route)
+	walkRoutes(inner, route{}, func(r route) {
+		exported := r.export()
+		routes[r.choiceTraceKey()] = &exported
+	})
+
+	return realMatcher{
+		minLength: inner.minLength,
+		maxLength: inner.maxLength,
+		notFound:  inner.notFound,
+		node:      node{kind: nodeWithRoute, children: []realMatcher{inner}},
+		accept: func(path []string, s *matchState) HandlerFunc {
+			// NOTE: Within nested WithRoute(), the outer trace needs to be retained.
+			previous := s.traceChoices
+			offset := len(s.choiceTrace)
+			s.traceChoices = true
+			handlerFunc := inner.accept(path, s)
+			s.traceChoices = previous
+			if handlerFunc == nil {
+				return nil
+			}
+
+			r := routes[string(s.choiceTrace[offset:])]
+			if !previous {
+				s.choiceTrace = s.choiceTrace[:offset]
+			}
+			next := middleware(*r, handlerFunc)
+			return func(w http.ResponseWriter, req *http.Request, vars map[string]string) {
+				next(w, req.WithContext(context.WithValue(req.Context(), matchedRouteKey{}, r)), vars)
+			}
+		},
+	}
+}
+
+// choiceTraceKey returns the value that matchState.choiceTrace will have after a request for this route has been accepted.
+func (r route) choiceTraceKey() string {
+	var buf []byte
+	for _, idx := range slices.Backward(r.choices) {
+		buf = binary.AppendUvarint(buf, uint64(idx))
+	}
+	return string(buf)
+}
+
+// matchedRouteKey is the context key for the value returned by MatchedRoute().
+type matchedRouteKey struct{}
+
+// MatchedRoute returns the [Route] that the request was matched to by the closest enclosing [WithRoute].
+// If the request was not routed through WithRoute(), None is returned.
+//
+// Like for the middleware given to WithRoute(), the Route must not be modified.
+func MatchedRoute(r *http.Request) Option[Route] {
+	route, ok := r.Context().Value(matchedRouteKey{}).(*Route)
+	if !ok {
+		return None[Route]()
+	}
+	return Some(*route)
+}
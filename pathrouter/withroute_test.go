@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pathrouter_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	"go.xyrillian.de/gg/microprom"
+	pr "go.xyrillian.de/gg/pathrouter"
+)
+
+func TestWithRoute(t *testing.T) {
+	var log []string
+	logging := func(route pr.Route, next pr.HandlerFunc) pr.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+			log = append(log, fmt.Sprintf("%s %s -> route %q (name %q)", r.Method, r.URL.Path, route.Pattern, route.Name))
+			next(w, r, vars)
+		}
+	}
+
+	handleWithLog := func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		route := pr.MatchedRoute(r).UnwrapOr(pr.Route{Pattern: "none"})
+		log = append(log, fmt.Sprintf("handler sees route %q", route.Pattern))
+	}
+	// a Handlers() instance that appears in multiple routes
+	shared := pr.Handlers(pr.ByMethod{http.MethodGet: handleWithLog})
+
+	m := pr.WithRoute(logging, pr.Choice(
+		buildObjectsAPI(),
+		pr.Element("v2", pr.Variable("id", pr.Named("get-object", pr.Handlers(pr.ByMethod{http.MethodGet: noop})))),
+		pr.Element("static", pr.Mount(http.NotFoundHandler())),
+		pr.Element("a", shared),
+		pr.Element("b", pr.Variable("id", shared)),
+		pr.Element("c", pr.Choice(
+			pr.Element("d", shared),
+			pr.Element("e", shared),
+			pr.Element("f", shared),
+			pr.Element("g", shared), // Element() options from here on are dispatched through a lookup table
+			pr.Variable("id", shared),
+		)),
+		pr.Element("nested", pr.WithRoute(logging, pr.Variable("id", shared))),
+	))
+	check := func(method, path string, expectedLog ...string) {
+		t.Helper()
+		log = nil
+		req := httptest.NewRequest(method, "http://localhost"+path, http.NoBody)
+		m.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, strings.Join(log, "\n"), strings.Join(expectedLog, "\n"))
+	}
+
+	check("GET", "/v1/objects/", `GET /v1/objects/ -> route "/v1/objects" (name "")`)
+	check("PATCH", "/v1/objects/42", `PATCH /v1/objects/42 -> route "/v1/objects/:id" (name "")`)
+	check("PUT", "/v1/objects/42/foo/bar/blob/", `PUT /v1/objects/42/foo/bar/blob/ -> route "/v1/objects/:id/*path/blob/" (name "")`)
+	check("GET", "/v2/42", `GET /v2/42 -> route "/v2/:id" (name "get-object")`)
+	check("GET", "/static/css/main.css", `GET /static/css/main.css -> route "/static/*" (name "")`)
+	// responses generated by Handlers() are reported with their route as well
+	check("POST", "/v2/42", `POST /v2/42 -> route "/v2/:id" (name "get-object")`)
+	// middleware is not invoked for requests that are not matched
+	check("GET", "/v3")
+
+	// routes are identified correctly even when they share the same Handlers() instance
+	check("GET", "/a", `GET /a -> route "/a" (name "")`, `handler sees route "/a"`)
+	check("GET", "/b/42", `GET /b/42 -> route "/b/:id" (name "")`, `handler sees route "/b/:id"`)
+	check("GET", "/c/f", `GET /c/f -> route "/c/f" (name "")`, `handler sees route "/c/f"`)
+	check("GET", "/c/g", `GET /c/g -> route "/c/g" (name "")`, `handler sees route "/c/g"`)
+	check("GET", "/c/h", `GET /c/h -> route "/c/:id" (name "")`, `handler sees route "/c/:id"`)
+
+	// nested WithRoute() reports patterns relative to its position, and MatchedRoute() reports the innermost one
+	check("GET", "/nested/42",
+		`GET /nested/42 -> route "/nested/:id" (name "")`,
+		`GET /nested/42 -> route "/:id" (name "")`,
+		`handler sees route "/:id"`,
+	)
+
+	// MatchedRoute() does not report anything outside of WithRoute()
+	log = nil
+	handleWithLog(httptest.NewRecorder(), httptest.NewRequest("GET", "http://localhost/a", http.NoBody), nil)
+	assert.Equal(t, strings.Join(log, "\n"), `handler sees route "none"`)
+
+	// WithRoute() is transparent to introspection
+	assert.Equal(t, pr.Routes(m), []pr.Route{
+		{Pattern: "/v1/objects", Methods: []string{"GET", "HEAD"}},
+		{Pattern: "/v1/objects/new", Methods: []string{"POST"}},
+		{Pattern: "/v1/objects/:id", Methods: []string{"DELETE", "GET", "HEAD", "PATCH"}},
+		{Pattern: "/v1/objects/:id/*path/blob/", Methods: []string{"PUT"}},
+		{Pattern: "/v2/:id", Methods: []string{"GET", "HEAD"}, Name: "get-object"},
+		{Pattern: "/static/*"},
+		{Pattern: "/a", Methods: []string{"GET", "HEAD"}},
+		{Pattern: "/b/:id", Methods: []string{"GET", "HEAD"}},
+		{Pattern: "/c/d", Methods: []string{"GET", "HEAD"}},
+		{Pattern: "/c/e", Methods: []string{"GET", "HEAD"}},
+		{Pattern: "/c/f", Methods: []string{"GET", "HEAD"}},
+		{Pattern: "/c/g", Methods: []string{"GET", "HEAD"}},
+		{Pattern: "/c/:id", Methods: []string{"GET", "HEAD"}},
+		{Pattern: "/nested/:id", Methods: []string{"GET", "HEAD"}},
+	})
+
+	msg := assert.PanicsWith[string](t, func() { pr.WithRoute(nil, pr.Handlers(nil)) })
+	assert.Equal(t, msg, `WithRoute() called with middleware = nil`)
+}
+
+func TestRequestMetrics(t *testing.T) {
+	var rm pr.RequestMetrics
+	m := pr.WithRoute(rm.Middleware, pr.Element("v1", pr.Variable("id", pr.Handlers(pr.ByMethod{
+		http.MethodGet: func(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+			if vars["id"] == "missing" {
+				http.Error(w, "not found", http.StatusNotFound)
+			} else {
+				fmt.Fprintln(w, "ok")
+			}
+		},
+		http.MethodDelete: noop,
+	}))))
+
+	for _, req := range []string{"GET /v1/1", "GET /v1/2", "GET /v1/missing", "DELETE /v1/1", "PUT /v1/1", "FROBNICATE /v1/1", "GET /v2/1"} {
+		method, path, _ := strings.Cut(req, " ")
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "http://localhost"+path, http.NoBody))
+	}
+
+	h := microprom.Handler{
+		Families:   rm.Families(),
+		Collect:    rm.Collect,
+		SortOutput: true,
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://localhost/metrics", http.NoBody))
+	body, err := io.ReadAll(rec.Result().Body)
+	assert.ErrEqual(t, err, nil)
+
+	// durations are not deterministic, so only the request counts are checked exactly
+	var requestCountLines []string
+	durationLineCount := 0
+	for line := range strings.Lines(string(body)) {
+		switch {
+		case strings.HasPrefix(line, "http_requests_total"):
+			requestCountLines = append(requestCountLines, line)
+		case strings.HasPrefix(line, "http_request_duration_seconds_total{"):
+			durationLineCount++
+		}
+	}
+	assert.Equal(t, strings.Join(requestCountLines, ""), strings.TrimSpace(`
+http_requests_total{method="DELETE",route="/v1/:id",code="200"} 1
+http_requests_total{method="GET",route="/v1/:id",code="200"} 2
+http_requests_total{method="GET",route="/v1/:id",code="404"} 1
+http_requests_total{method="OTHER",route="/v1/:id",code="405"} 1
+http_requests_total{method="PUT",route="/v1/:id",code="405"} 1
+	`)+"\n")
+	assert.Equal(t, durationLineCount, 5)
+}
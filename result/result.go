@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+// Package result provides a [Result] type for Go, as a companion to the Option type from package option.
+// A value of the Result type will be in one of two states: "Ok" (containing a value) or "Err" (containing an error).
+//
+// Where standard Go uses a (T, error) pair, a Result[T] holds the same information in a single value.
+// This allows for fallible values to be stored in struct fields, sent over channels, or yielded by iterators:
+//
+//	// instead of this...
+//	type job struct {
+//		output []byte
+//		err    error
+//	}
+//	jobs := make(chan job)
+//	go func() {
+//		output, err := cmd.Output()
+//		jobs <- job{output, err}
+//	}()
+//
+//	// ...we can write this
+//	jobs := make(chan Result[[]byte])
+//	go func() {
+//		jobs <- results.From(cmd.Output())
+//	}()
+//
+// Result values are converted back into a (T, error) pair with [Result.Unpack], for use with the usual error handling idioms.
+//
+// # Clean import guarantee
+//
+// Like package option, this package is supposed to be used as a dot-import:
+//
+//	import . "go.xyrillian.de/gg/result"
+//
+// To avoid backwards incompatibilities, we guarantee that, at least throughout the 1.x series,
+// newer versions of this package will never export any more names than it currently does ("Err", "Ok" and "Result").
+// These names do not collide with the names exported by package option, so both packages can be dot-imported at the same time.
+// Functions that cannot be expressed as methods on the Result type (e.g. because they introduce additional type parameters)
+// can be found in "package results" that sits next to this package.
+//
+// # Zero value
+//
+// Since Go does not allow for types without a zero value, the zero value of Result[T] is Ok containing the zero value of T.
+// This matches the behavior of a (T, error) pair where both values are zero.
+package result // import "go.xyrillian.de/gg/result"
+
+import (
+	"fmt"
+	"iter"
+
+	"go.xyrillian.de/gg/errext"
+	. "go.xyrillian.de/gg/option"
+)
+
+// Result is a type that contains either an instance of T or an error.
+type Result[T any] struct {
+	// NOTE: The zero value of this type must be equal to `Ok` with the zero value of T.
+	value T
+	err   error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// constructors
+
+// Ok constructs a Result instance that contains the provided value.
+func Ok[T any](value T) Result[T] {
+	return Result[T]{value, nil}
+}
+
+// Err constructs a Result instance that contains the provided error.
+// Since an Err result must contain an error, this function panics if err is nil.
+func Err[T any](err error) Result[T] {
+	if err == nil {
+		panic("result.Err() called with err = nil")
+	}
+	var empty T
+	return Result[T]{empty, err}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// core API (methods sorted by name)
+
+// AsOption converts this Result into an Option, discarding the error (if any).
+func (r Result[T]) AsOption() Option[T] {
+	if r.err == nil {
+		return Some(r.value)
+	} else {
+		return None[T]()
+	}
+}
+
+// IsErr returns whether the Result contains an error.
+// Its inverse is IsOk().
+func (r Result[T]) IsErr() bool {
+	return r.err != nil
+}
+
+// IsErrAnd returns whether the Result contains an error that matches the given predicate.
+func (r Result[T]) IsErrAnd(predicate func(error) bool) bool {
+	return r.err != nil && predicate(r.err)
+}
+
+// IsOk returns whether the Result contains a value.
+// Its inverse is IsErr().
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// IsOkAnd returns whether the Result contains a value that matches the given predicate.
+//
+// See package go.xyrillian.de/gg/is for pre-made predicates.
+func (r Result[T]) IsOkAnd(predicate func(T) bool) bool {
+	return r.err == nil && predicate(r.value)
+}
+
+// Iter returns an iterator that yields the contained value once (if any).
+// If the Result contains an error, the iterator yields nothing.
+//
+// This is equivalent to r.AsOption().Iter().
+func (r Result[T]) Iter() iter.Seq[T] {
+	if r.err == nil {
+		return func(yield func(T) bool) { yield(r.value) }
+	} else {
+		return func(yield func(T) bool) {}
+	}
+}
+
+// Iter2 returns an iterator that yields the contained value and error once.
+// This is useful for passing a single Result into functions that consume an iter.Seq2[T, error].
+func (r Result[T]) Iter2() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) { yield(r.value, r.err) }
+}
+
+// Or returns the result itself if it contains a value, or otherwise returns "other".
+//
+// If you are passing the result of a function call, consider using OrElse() to avoid calling the function unless necessary.
+func (r Result[T]) Or(other Result[T]) Result[T] {
+	if r.err == nil {
+		return r
+	} else {
+		return other
+	}
+}
+
+// OrElse returns the result itself if it contains a value, or otherwise runs the provided closure on the contained error to produce the return value.
+func (r Result[T]) OrElse(closure func(error) Result[T]) Result[T] {
+	if r.err == nil {
+		return r
+	} else {
+		return closure(r.err)
+	}
+}
+
+// Unpack returns the contained value (or the zero value if Err), as well as the contained error (or nil if Ok).
+func (r Result[T]) Unpack() (T, error) {
+	return r.value, r.err
+}
+
+// UnwrapOr returns the contained value.
+// If the Result contains an error, the provided fallback value is returned instead.
+func (r Result[T]) UnwrapOr(fallback T) T {
+	if r.err == nil {
+		return r.value
+	} else {
+		return fallback
+	}
+}
+
+// UnwrapOrElse returns the contained value.
+// If the Result contains an error, the provided closure is used to produce the return value from that error.
+func (r Result[T]) UnwrapOrElse(closure func(error) T) T {
+	if r.err == nil {
+		return r.value
+	} else {
+		return closure(r.err)
+	}
+}
+
+// UnwrapOrPanic returns the contained value, or panics if the Result contains an error.
+// The panic message is formed by appending the error message to the given message, e.g. "cannot load config: file not found".
+// Like for [Option.UnwrapOrPanic], the message may have any type; it is formatted with the %v verb.
+func (r Result[T]) UnwrapOrPanic(msg any) T {
+	if r.err == nil {
+		return r.value
+	} else {
+		panic(fmt.Sprintf("%v: %s", msg, r.err.Error()))
+	}
+}
+
+// WithCleanup combines the contained error (if any) with the error from a cleanup operation, as described for [errext.WithCleanup].
+// If cleanupErr is not nil, the result will contain an error, even if it previously contained a value.
+//
+//	func readConfig(path string) Result[Config] {
+//		f, err := os.Open(path)
+//		if err != nil {
+//			return Err[Config](err)
+//		}
+//		return results.From(parseConfig(f)).WithCleanup("f.Close", f.Close())
+//	}
+func (r Result[T]) WithCleanup(cleanupOperation string, cleanupErr error) Result[T] {
+	if cleanupErr == nil {
+		return r
+	}
+	return Err[T](errext.WithCleanup(r.err, cleanupOperation, cleanupErr))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// formatting support
+
+var _ fmt.Formatter = Result[bool]{}
+
+// Format implements the [fmt.Formatter] interface.
+//
+// If there is a contained value, it will be formatted as if it was given directly.
+// Otherwise, the string "<error: ...>" (containing the error message) will be formatted according to the specified width and flags.
+func (r Result[T]) Format(f fmt.State, verb rune) {
+	if r.err == nil {
+		fmt.Fprintf(f, fmt.FormatString(f, verb), r.value)
+	} else {
+		fmt.Fprintf(f, fmt.FormatString(f, 's'), "<error: "+r.err.Error()+">")
+	}
+}
@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package result_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	. "go.xyrillian.de/gg/option"
+	. "go.xyrillian.de/gg/result"
+)
+
+var errFoo = errors.New("foo")
+
+func TestZeroValue(t *testing.T) {
+	var zero Result[string]
+	assert.Equal(t, zero, Ok(""))
+}
+
+func TestErr(t *testing.T) {
+	msg := assert.PanicsWith[string](t, func() { Err[int](nil) })
+	assert.Equal(t, msg, "result.Err() called with err = nil")
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// core API (methods sorted by name)
+
+func TestAsOption(t *testing.T) {
+	assert.Equal(t, Err[int](errFoo).AsOption(), None[int]())
+	assert.Equal(t, Ok(42).AsOption(), Some(42))
+}
+
+func isEven(x int) bool {
+	// an example predicate for testing the various functions that take predicates
+	return x%2 == 0
+}
+
+func isErrFoo(err error) bool {
+	return errors.Is(err, errFoo)
+}
+
+func TestIsErr(t *testing.T) {
+	assert.Equal(t, Err[int](errFoo).IsErr(), true)
+	assert.Equal(t, Ok(42).IsErr(), false)
+}
+
+func TestIsErrAnd(t *testing.T) {
+	assert.Equal(t, Err[int](errFoo).IsErrAnd(isErrFoo), true)
+	assert.Equal(t, Err[int](errors.New("bar")).IsErrAnd(isErrFoo), false)
+	assert.Equal(t, Ok(42).IsErrAnd(isErrFoo), false)
+}
+
+func TestIsOk(t *testing.T) {
+	assert.Equal(t, Err[int](errFoo).IsOk(), false)
+	assert.Equal(t, Ok(42).IsOk(), true)
+}
+
+func TestIsOkAnd(t *testing.T) {
+	assert.Equal(t, Err[int](errFoo).IsOkAnd(isEven), false)
+	assert.Equal(t, Ok(41).IsOkAnd(isEven), false)
+	assert.Equal(t, Ok(42).IsOkAnd(isEven), true)
+}
+
+func TestIter(t *testing.T) {
+	assert.Equal(t, slices.Collect(Err[int](errFoo).Iter()), []int(nil))
+	assert.Equal(t, slices.Collect(Ok(42).Iter()), []int{42})
+}
+
+func TestIter2(t *testing.T) {
+	var log []string
+	for value, err := range Err[int](errFoo).Iter2() {
+		log = append(log, fmt.Sprintf("%d, %v", value, err))
+	}
+	for value, err := range Ok(42).Iter2() {
+		log = append(log, fmt.Sprintf("%d, %v", value, err))
+	}
+	assert.Equal(t, log, []string{"0, foo", "42, <nil>"})
+}
+
+func TestOr(t *testing.T) {
+	err := Err[int](errFoo)
+	assert.Equal(t, err.Or(err), err)
+	assert.Equal(t, Ok(42).Or(err), Ok(42))
+	assert.Equal(t, err.Or(Ok(23)), Ok(23))
+	assert.Equal(t, Ok(42).Or(Ok(23)), Ok(42))
+}
+
+func TestOrElse(t *testing.T) {
+	fallback := func(err error) Result[int] { return Ok(len(err.Error())) }
+	assert.Equal(t, Err[int](errFoo).OrElse(fallback), Ok(3))
+	assert.Equal(t, Ok(42).OrElse(fallback), Ok(42))
+}
+
+func TestUnpack(t *testing.T) {
+	value, err := Err[int](errFoo).Unpack()
+	assert.Equal(t, value, 0)
+	assert.Equal(t, err, errFoo)
+
+	value, err = Ok(42).Unpack()
+	assert.Equal(t, value, 42)
+	assert.Equal(t, err, nil)
+}
+
+func TestUnwrapOr(t *testing.T) {
+	assert.Equal(t, Err[int](errFoo).UnwrapOr(23), 23)
+	assert.Equal(t, Ok(42).UnwrapOr(23), 42)
+}
+
+func TestUnwrapOrElse(t *testing.T) {
+	fallback := func(err error) int { return len(err.Error()) }
+	assert.Equal(t, Err[int](errFoo).UnwrapOrElse(fallback), 3)
+	assert.Equal(t, Ok(42).UnwrapOrElse(fallback), 42)
+}
+
+func TestUnwrapOrPanic(t *testing.T) {
+	assert.Equal(t, Ok(42).UnwrapOrPanic("cannot get value"), 42)
+	msg := assert.PanicsWith[string](t, func() { Err[int](errFoo).UnwrapOrPanic("cannot get value") })
+	assert.Equal(t, msg, "cannot get value: foo")
+
+	// like for Option.UnwrapOrPanic(), the message may be of any type
+	msg = assert.PanicsWith[string](t, func() { Err[int](errFoo).UnwrapOrPanic(errors.New("cannot get value")) })
+	assert.Equal(t, msg, "cannot get value: foo")
+}
+
+func TestWithCleanup(t *testing.T) {
+	errClose := errors.New("already closed")
+	assert.Equal(t, Ok(42).WithCleanup("Close", nil), Ok(42))
+	assert.Equal(t, Err[int](errFoo).WithCleanup("Close", nil), Err[int](errFoo))
+
+	_, err := Ok(42).WithCleanup("Close", errClose).Unpack()
+	assert.ErrEqual(t, err, "during Close(): already closed")
+	_, err = Err[int](errFoo).WithCleanup("Close", errClose).Unpack()
+	assert.ErrEqual(t, err, "foo (additional error during Close(): already closed)")
+	assert.Equal(t, errors.Is(err, errFoo), true)
+	assert.Equal(t, errors.Is(err, errClose), true)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// formatting support
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, fmt.Sprintf("%d", Ok(42)), "42")
+	assert.Equal(t, fmt.Sprintf("%5d", Ok(42)), "   42")
+	assert.Equal(t, fmt.Sprintf("%d", Err[int](errFoo)), "<error: foo>")
+	assert.Equal(t, fmt.Sprintf("%-15d|", Err[int](errFoo)), "<error: foo>   |")
+}
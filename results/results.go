@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+// Package results provides additional functions for type result.Result
+// that cannot be expressed as methods on the Result type itself.
+package results // import "go.xyrillian.de/gg/results"
+
+import (
+	. "go.xyrillian.de/gg/option"
+	. "go.xyrillian.de/gg/result"
+)
+
+// NOTE: Keep functions sorted by name.
+
+// From converts a (T, error) pair into a Result[T].
+// It is intended to wrap function calls with the usual pair of return values:
+//
+//	config := results.From(loadConfig(path))
+//
+// If err is not nil, the value is discarded.
+func From[T any](value T, err error) Result[T] {
+	if err == nil {
+		return Ok(value)
+	} else {
+		return Err[T](err)
+	}
+}
+
+// FromOption converts an Option[T] into a Result[T], using the given error if the Option is empty.
+// The error must not be nil, in the same way as for result.Err().
+func FromOption[T any](o Option[T], err error) Result[T] {
+	if value, ok := o.Unpack(); ok {
+		return Ok(value)
+	} else {
+		return Err[T](err)
+	}
+}
+
+// Map applies the given function to the value contained in r, if there is one.
+func Map[T, U any](r Result[T], mapping func(T) U) Result[U] {
+	if t, err := r.Unpack(); err == nil {
+		return Ok(mapping(t))
+	} else {
+		return Err[U](err)
+	}
+}
@@ -0,0 +1,32 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package results
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	. "go.xyrillian.de/gg/option"
+	. "go.xyrillian.de/gg/result"
+)
+
+var errFoo = errors.New("foo")
+
+func TestFrom(t *testing.T) {
+	assert.Equal(t, From(strconv.Atoi("42")), Ok(42))
+	_, err := From(strconv.Atoi("foo")).Unpack()
+	assert.ErrEqual(t, err, `strconv.Atoi: parsing "foo": invalid syntax`)
+}
+
+func TestFromOption(t *testing.T) {
+	assert.Equal(t, FromOption(None[int](), errFoo), Err[int](errFoo))
+	assert.Equal(t, FromOption(Some(42), errFoo), Ok(42))
+}
+
+func TestMap(t *testing.T) {
+	assert.Equal(t, Map(Err[int](errFoo), strconv.Itoa), Err[string](errFoo))
+	assert.Equal(t, Map(Ok(42), strconv.Itoa), Ok("42"))
+}
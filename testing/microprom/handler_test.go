@@ -5,6 +5,7 @@ package microprom_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"maps"
@@ -15,8 +16,11 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 	"go.xyrillian.de/gg/assert"
 	"go.xyrillian.de/gg/microprom"
+	"google.golang.org/protobuf/encoding/prototext"
 )
 
 func TestHandlerFunctionallyIdenticalToPromhttp(t *testing.T) {
@@ -76,6 +80,122 @@ func TestHandlerFunctionallyIdenticalToPromhttp(t *testing.T) {
 	body2, headers2 = getMetrics(t, h2, http.Header{"Accept": {"application/openmetrics-text; version=1.0.0"}})
 	assert.Equal(t, strings.Split(body1, "\n"), strings.Split(body2, "\n"))
 	assert.Equal(t, headers1, headers2)
+
+	// test identical behavior for protobuf format
+	body1, headers1 = getMetrics(t, h1, http.Header{"Accept": {protobufFormat}})
+	body2, headers2 = getMetrics(t, h2, http.Header{"Accept": {protobufFormat}})
+	assert.Equal(t, decodeProtobuf(t, body1), decodeProtobuf(t, body2))
+	assert.Equal(t, headers1, headers2)
+}
+
+func TestHistogramAndSummaryFunctionallyIdenticalToPromhttp(t *testing.T) {
+	// build a microprom.Handler rendering a histogram and a summary
+	h1 := microprom.Handler{
+		Families: map[microprom.MetricFamilyName]microprom.MetricFamilyInfo{
+			"request_duration_seconds": {
+				Type: microprom.MetricTypeHistogram,
+				Help: "How long requests took.",
+			},
+			"response_size_bytes": {
+				Type: microprom.MetricTypeSummary,
+				Help: "How large responses were.",
+			},
+		},
+		SortOutput: true,
+		Collect: func(ctx context.Context, ms *microprom.MetricSet) error {
+			labels := ms.FormatLabels(microprom.NewLabelNames("method"), "GET")
+			ms.AddHistogram("request_duration_seconds", labels, []microprom.Bucket{
+				{UpperBound: 0.1, CumulativeCount: 2},
+				{UpperBound: 1, CumulativeCount: 5},
+			}, 3.25, 6)
+			ms.AddSummary("response_size_bytes", labels, []microprom.Quantile{
+				{Quantile: 0.5, Value: 1024},
+				{Quantile: 0.99, Value: 65536.5},
+			}, 1e6, 400)
+			return nil
+		},
+	}
+
+	// build a promhttp.Handler rendering the same metric families
+	r := prometheus.NewRegistry()
+	r.MustRegister(constCollector{
+		prometheus.MustNewConstHistogram(
+			prometheus.NewDesc("request_duration_seconds", "How long requests took.", []string{"method"}, nil),
+			6, 3.25, map[float64]uint64{0.1: 2, 1: 5}, "GET",
+		),
+		prometheus.MustNewConstSummary(
+			prometheus.NewDesc("response_size_bytes", "How large responses were.", []string{"method"}, nil),
+			400, 1e6, map[float64]float64{0.5: 1024, 0.99: 65536.5}, "GET",
+		),
+	})
+	h2 := promhttp.HandlerFor(r, promhttp.HandlerOpts{EnableOpenMetrics: true})
+
+	// test identical behavior for Prometheus Text Format
+	body1, headers1 := getMetrics(t, h1, nil)
+	body2, headers2 := getMetrics(t, h2, nil)
+	assert.Equal(t, strings.Split(body1, "\n"), strings.Split(body2, "\n"))
+	assert.Equal(t, headers1, headers2)
+
+	// test identical behavior for OpenMetrics 1.0 text format
+	body1, headers1 = getMetrics(t, h1, http.Header{"Accept": {"application/openmetrics-text; version=1.0.0"}})
+	body2, headers2 = getMetrics(t, h2, http.Header{"Accept": {"application/openmetrics-text; version=1.0.0"}})
+	assert.Equal(t, strings.Split(body1, "\n"), strings.Split(body2, "\n"))
+	assert.Equal(t, headers1, headers2)
+
+	// test identical behavior for protobuf format
+	body1, headers1 = getMetrics(t, h1, http.Header{"Accept": {protobufFormat}})
+	body2, headers2 = getMetrics(t, h2, http.Header{"Accept": {protobufFormat}})
+	assert.Equal(t, decodeProtobuf(t, body1), decodeProtobuf(t, body2))
+	assert.Equal(t, headers1, headers2)
+}
+
+// constCollector is a prometheus.Collector that reports a fixed set of metrics.
+type constCollector []prometheus.Metric
+
+func (c constCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, m := range c {
+		ch <- m.Desc()
+	}
+}
+
+func (c constCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, m := range c {
+		ch <- m
+	}
+}
+
+const protobufFormat = "application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited"
+
+// decodeProtobuf decodes a response body in the protobuf format into a readable representation of each MetricFamily message.
+//
+// Since microprom does not emit created timestamps in the protobuf format, these are removed from the decoded messages.
+// Otherwise, the output of client_golang's CounterVec (which records the creation time of each counter) could not be compared.
+func decodeProtobuf(t *testing.T, body string) []string {
+	t.Helper()
+	var result []string
+	dec := expfmt.NewDecoder(strings.NewReader(body), expfmt.NewFormat(expfmt.TypeProtoDelim))
+	for {
+		var mf dto.MetricFamily
+		err := dec.Decode(&mf)
+		if errors.Is(err, io.EOF) {
+			return result
+		}
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		for _, m := range mf.Metric {
+			if m.Counter != nil {
+				m.Counter.CreatedTimestamp = nil
+			}
+			if m.Histogram != nil {
+				m.Histogram.CreatedTimestamp = nil
+			}
+			if m.Summary != nil {
+				m.Summary.CreatedTimestamp = nil
+			}
+		}
+		result = append(result, prototext.Format(&mf))
+	}
 }
 
 func getMetrics(t *testing.T, h http.Handler, requestHeaders http.Header) (responseBody string, responseHeaders http.Header) {
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2026 Stefan Majewsky <majewsky@gmx.net>
+// SPDX-License-Identifier: Apache-2.0
+
+package pgruntime_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.xyrillian.de/gg/assert"
+	. "go.xyrillian.de/gg/option"
+)
+
+// This test checks that Option.Scan() works with the actual wire format of Postgres columns.
+// It lives here because it needs a test database.
+func TestScanOptionFromPostgres(t *testing.T) {
+	ctx := t.Context()
+	db, _ := connector.ConnectForTest(t, defaultBehavior)
+
+	type payload struct {
+		Name Option[string] `json:"name"`
+		Tags []string       `json:"tags"`
+	}
+
+	// scalar columns
+	o1, err := selectOneValue[Option[int64]](ctx, db, `SELECT NULL::BIGINT`)
+	if assert.ErrEqual(t, err, nil) {
+		assert.Equal(t, o1, None[int64]())
+	}
+	o2, err := selectOneValue[Option[int64]](ctx, db, `SELECT 42::BIGINT`)
+	if assert.ErrEqual(t, err, nil) {
+		assert.Equal(t, o2, Some[int64](42))
+	}
+
+	// JSON and JSONB columns
+	o3, err := selectOneValue[Option[payload]](ctx, db, `SELECT NULL::JSONB`)
+	if assert.ErrEqual(t, err, nil) {
+		assert.Equal(t, o3, None[payload]())
+	}
+	o4, err := selectOneValue[Option[payload]](ctx, db, `SELECT '{"name":"foo","tags":["bar"]}'::JSONB`)
+	if assert.ErrEqual(t, err, nil) {
+		assert.Equal(t, o4, Some(payload{Name: Some("foo"), Tags: []string{"bar"}}))
+	}
+	o5, err := selectOneValue[Option[json.RawMessage]](ctx, db, `SELECT '[1, 2]'::JSON`)
+	if assert.ErrEqual(t, err, nil) {
+		assert.Equal(t, o5, Some(json.RawMessage(`[1, 2]`)))
+	}
+	o6, err := selectOneValue[Option[[]payload]](ctx, db, `SELECT '[{"name":null,"tags":[]}]'::JSONB`)
+	if assert.ErrEqual(t, err, nil) {
+		assert.Equal(t, o6, Some([]payload{{Name: None[string](), Tags: []string{}}}))
+	}
+
+	// array columns
+	o7, err := selectOneValue[Option[[]string]](ctx, db, `SELECT NULL::TEXT[]`)
+	if assert.ErrEqual(t, err, nil) {
+		assert.Equal(t, o7, None[[]string]())
+	}
+	o8, err := selectOneValue[Option[[]string]](ctx, db, `SELECT '{}'::TEXT[]`)
+	if assert.ErrEqual(t, err, nil) {
+		assert.Equal(t, o8, Some([]string{}))
+	}
+	o9, err := selectOneValue[Option[[]string]](ctx, db, `SELECT ARRAY['foo', 'bar baz', 'with "quotes", commas and \backslashes', 'NULL']`)
+	if assert.ErrEqual(t, err, nil) {
+		assert.Equal(t, o9, Some([]string{"foo", "bar baz", `with "quotes", commas and \backslashes`, "NULL"}))
+	}
+	o10, err := selectOneValue[Option[[]Option[int64]]](ctx, db, `SELECT ARRAY[1, NULL, 3]::BIGINT[]`)
+	if assert.ErrEqual(t, err, nil) {
+		assert.Equal(t, o10, Some([]Option[int64]{Some[int64](1), None[int64](), Some[int64](3)}))
+	}
+	o11, err := selectOneValue[Option[[]bool]](ctx, db, `SELECT ARRAY[TRUE, FALSE]`)
+	if assert.ErrEqual(t, err, nil) {
+		assert.Equal(t, o11, Some([]bool{true, false}))
+	}
+	o12, err := selectOneValue[Option[[]payload]](ctx, db, `SELECT ARRAY['{"name":"foo","tags":null}'::JSONB]`)
+	if assert.ErrEqual(t, err, nil) {
+		assert.Equal(t, o12, Some([]payload{{Name: Some("foo")}}))
+	}
+}